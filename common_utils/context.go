@@ -53,6 +53,11 @@ const (
 	DBUS_CONFIG_RELOAD
 	DBUS_STOP_SERVICE
 	DBUS_RESTART_SERVICE
+	DBUS_REBOOT
+	DBUS_KILL_PROCESS
+	DBUS_INSTALL_IMAGE
+	DBUS_LIST_IMAGES
+	DBUS_SET_NEXT_BOOT
 	COUNTER_SIZE
 )
 
@@ -88,6 +93,16 @@ func (c CounterType) String() string {
 		return "DBUS stop service"
 	case DBUS_RESTART_SERVICE:
 		return "DBUS restart service"
+	case DBUS_REBOOT:
+		return "DBUS reboot"
+	case DBUS_KILL_PROCESS:
+		return "DBUS kill process"
+	case DBUS_INSTALL_IMAGE:
+		return "DBUS install image"
+	case DBUS_LIST_IMAGES:
+		return "DBUS list images"
+	case DBUS_SET_NEXT_BOOT:
+		return "DBUS set next boot"
 	default:
 		return ""
 	}
@@ -1,26 +1,38 @@
 package telemetry_dialout
 
 import (
-	// "encoding/json"
+	"bytes"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/rand"
 	spb "github.com/sonic-net/sonic-gnmi/proto"
 	sdc "github.com/sonic-net/sonic-gnmi/sonic_data_client"
 	sdcfg "github.com/sonic-net/sonic-gnmi/sonic_db_config"
 	"github.com/Workiva/go-datastructures/queue"
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-redis/redis"
 	log "github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	gpb "github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/openconfig/ygot/ygot"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+	"math/big"
 	"net"
+	"os"
+	"path/filepath"
 	//"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -38,6 +50,42 @@ const (
 // Type defines the type of report.
 type reportType int
 
+const (
+	// Failover publishes to one destination of the group at a time and
+	// moves to the next one upon failure.
+	Failover dstMode = iota
+	// Broadcast publishes every notification to all destinations of the group,
+	// a failed destination is skipped until it reconnects.
+	Broadcast
+	// Hash shards the notification paths across the connected destinations of
+	// the group.
+	Hash
+)
+
+// dstMode defines how the destinations of a group are used.
+type dstMode int
+
+// NewDstMode returns the dstMode named by s.
+func NewDstMode(s string) (dstMode, error) {
+	for m, name := range dstModeString {
+		if name == s {
+			return m, nil
+		}
+	}
+	return Failover, fmt.Errorf("unknown dst_mode %q", s)
+}
+
+// String returns the string representation of the dstMode.
+func (m dstMode) String() string {
+	return dstModeString[m]
+}
+
+// Lower bound of the exponential backoff between connection attempts
+const minRetryBackoff = 100 * time.Millisecond
+
+// Syslog level of the EVENTS data client, error
+const eventsLogLevel = 3
+
 // NewType returns a new reportType based on the provided string.
 func NewReportType(s string) reportType {
 	v, ok := typeConst[s]
@@ -70,10 +118,19 @@ var (
 	// Global mutex for protecting the config data
 	configMu sync.Mutex
 
+	dstModeString = map[dstMode]string{
+		Failover:  "failover",
+		Broadcast: "broadcast",
+		Hash:      "hash",
+	}
+
 	// Each Destination group may have more than one Destinations
-	// Only one destination will be used at one time
+	// How they are used depends on the dst_mode of the group
 	destGrpNameMap = make(map[string][]Destination)
 
+	// dst_mode of the Destination groups, failover by default
+	destGrpModeMap = make(map[string]dstMode)
+
 	// For finding clientSubscription quickly
 	ClientSubscriptionNameMap = make(map[string]*clientSubscription)
 
@@ -83,6 +140,109 @@ var (
 
 type Destination struct {
 	Addrs string
+	// TLS settings of the destination group, the global ClientConfig.TLS is used if nil
+	TLS *DestinationTLS
+}
+
+// DestinationTLS holds the TLS settings of a destination group. Certificate
+// files are read again whenever they are modified.
+type DestinationTLS struct {
+	CaCrt      string // CA bundle for server certificate validation
+	ClientCrt  string // Client certificate file
+	ClientKey  string // Client private key file
+	ServerName string // Server name override for certificate validation
+	Insecure   bool   // Skip server certificate validation
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+	cfg      *tls.Config
+	gen      uint64 // incremented on each reload
+	// gen the clients of the group were last connected with, guarded by configMu
+	appliedGen uint64
+}
+
+func (dt *DestinationTLS) files() []string {
+	var files []string
+	for _, f := range []string{dt.CaCrt, dt.ClientCrt, dt.ClientKey} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// refresh loads the TLS config again if it was never loaded or any of the
+// certificate files was modified since. It reports whether it was reloaded.
+func (dt *DestinationTLS) refresh() (bool, error) {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	modTimes := make(map[string]time.Time)
+	changed := dt.cfg == nil
+	for _, f := range dt.files() {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return false, fmt.Errorf("TLS file %v: %v", f, err)
+		}
+		modTimes[f] = fi.ModTime()
+		if !fi.ModTime().Equal(dt.modTimes[f]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	cfg := &tls.Config{}
+	if clientCfg != nil && clientCfg.TLS != nil {
+		cfg = clientCfg.TLS.Clone()
+	}
+	if dt.ServerName != "" {
+		cfg.ServerName = dt.ServerName
+	}
+	if dt.Insecure {
+		cfg.InsecureSkipVerify = true
+	}
+	if dt.CaCrt != "" {
+		ca, err := ioutil.ReadFile(dt.CaCrt)
+		if err != nil {
+			return false, fmt.Errorf("read CA certificate %v: %v", dt.CaCrt, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return false, fmt.Errorf("no valid CA certificate in %v", dt.CaCrt)
+		}
+		cfg.RootCAs = pool
+	}
+	if dt.ClientCrt != "" || dt.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(dt.ClientCrt, dt.ClientKey)
+		if err != nil {
+			return false, fmt.Errorf("load client certificate %v and key %v: %v", dt.ClientCrt, dt.ClientKey, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	dt.cfg = cfg
+	dt.modTimes = modTimes
+	dt.gen++
+	log.V(2).Infof("Loaded TLS config with CA %q, certificate %q, server name %q", dt.CaCrt, dt.ClientCrt, dt.ServerName)
+	return true, nil
+}
+
+// generation returns the number of times the TLS config was loaded.
+func (dt *DestinationTLS) generation() uint64 {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	return dt.gen
+}
+
+// config returns the current TLS config, reloading modified certificate files.
+func (dt *DestinationTLS) config() (*tls.Config, error) {
+	if _, err := dt.refresh(); err != nil {
+		return nil, err
+	}
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	return dt.cfg, nil
 }
 
 func (d Destination) Validate() error {
@@ -98,7 +258,7 @@ type ClientConfig struct {
 	SrcIp          string
 	RetryInterval  time.Duration
 	Encoding       gpb.Encoding
-	Unidirectional bool        // by default, no reponse from remote server
+	Unidirectional bool        // by default, no reponse from remote server, otherwise notifications are retained until acknowledged
 	TLS            *tls.Config // TLS config to use when connecting to target. Optional.
 	RedisConType   string      // "unix"  or "tcp"
 }
@@ -109,24 +269,29 @@ type clientSubscription struct {
 	// Config Data
 	name          string
 	destGroupName string
+	origin        string // selects the data client like gNMI Subscribe
 	prefix        *gpb.Path
 	paths         []*gpb.Path
 	reportType    reportType
 	interval      time.Duration // report interval
+	dstMode       dstMode       // how the destinations of the group are used
 
 	// Running time data
 	cMu    sync.Mutex
-	client *Client              // GNMIDialOutClient
+	conns  []*destConn          // destinations in use, with their GNMIDialOutClients
 	dc     sdc.Client           // SONiC data client
 	stop   chan struct{}        // Inform publishRun routine to stop
 	q      *queue.PriorityQueue // for data passing among go routine
 	w      sync.WaitGroup       // Wait for all sub go routine to finish
 	opened bool                 // whether there is opened instance for this client subscription
 	cancel context.CancelFunc
+	// Notifications not yet acknowledged by the remote servers, shared by
+	// all instances of this client subscription
+	unacked *unackedStore
 
 	conTryCnt uint64 //Number of time trying to connect
 	sendMsg   uint64
-	recvMsg   uint64
+	recvMsg   uint64 // updated by the acknowledgement receivers, accessed atomically
 	errors    uint64
 }
 
@@ -145,6 +310,615 @@ type Client struct {
 	recvMsg uint64
 }
 
+// destConn is the connection to one destination in use. In broadcast and
+// hash modes a destination failing to send is reconnected on its own backoff,
+// while the other destinations keep receiving the notifications.
+type destConn struct {
+	dest     Destination
+	client   *Client       // nil while disconnected, guarded by cMu of the client subscription
+	acksDone chan struct{} // closed when the remote server ends the stream
+}
+
+// Maximum number of unacknowledged notifications retained per destination
+const maxUnackedNotifications = 1000
+
+// unackedStore keeps the notifications sent in acknowledged mode until the
+// remote server acknowledges them with a PublishResponse, so that they can
+// be retransmitted after a reconnect. They are kept per destination, so that
+// the acknowledgements of one destination leave those of the others pending.
+type unackedStore struct {
+	mu    sync.Mutex
+	resps map[string][]*gpb.SubscribeResponse // by destination
+}
+
+func newUnackedStore() *unackedStore {
+	return &unackedStore{resps: make(map[string][]*gpb.SubscribeResponse)}
+}
+
+// add retains resp for dest, dropping the oldest notification of dest when
+// it has too many.
+func (s *unackedStore) add(dest string, resp *gpb.SubscribeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resps := s.resps[dest]
+	if len(resps) >= maxUnackedNotifications {
+		log.V(1).Infof("Unacknowledged notification store of %v full, dropping %v", dest, resps[0])
+		resps[0] = nil
+		resps = resps[1:]
+	}
+	s.resps[dest] = append(resps, resp)
+}
+
+// ack removes the paths acknowledged by dest from its retained notifications
+// with the same timestamp and prefix. A PublishResponse without paths
+// acknowledges the whole notification. It returns the number of notifications
+// removed.
+func (s *unackedStore) ack(dest string, pr *spb.PublishResponse) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acked := func(path *gpb.Path) bool {
+		if len(pr.GetPath()) == 0 {
+			return true
+		}
+		for _, p := range pr.GetPath() {
+			if proto.Equal(p, path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	removed := 0
+	old := s.resps[dest]
+	resps := old[:0]
+	for _, resp := range old {
+		n := resp.GetUpdate()
+		if n.GetTimestamp() != pr.GetTimestamp() || !proto.Equal(n.GetPrefix(), pr.GetPrefix()) {
+			resps = append(resps, resp)
+			continue
+		}
+		rest := &gpb.Notification{
+			Timestamp: n.GetTimestamp(),
+			Prefix:    n.GetPrefix(),
+			Alias:     n.GetAlias(),
+			Atomic:    n.GetAtomic(),
+		}
+		for _, u := range n.GetUpdate() {
+			if !acked(u.GetPath()) {
+				rest.Update = append(rest.Update, u)
+			}
+		}
+		for _, d := range n.GetDelete() {
+			if !acked(d) {
+				rest.Delete = append(rest.Delete, d)
+			}
+		}
+		if len(rest.Update) == 0 && len(rest.Delete) == 0 {
+			removed++
+			continue
+		}
+		resps = append(resps, &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: rest}})
+	}
+	// Release references held beyond the new length
+	for i := len(resps); i < len(old); i++ {
+		old[i] = nil
+	}
+	if len(resps) == 0 {
+		delete(s.resps, dest)
+	} else {
+		s.resps[dest] = resps
+	}
+	return removed
+}
+
+// pending returns the notifications retained for dest, oldest first.
+func (s *unackedStore) pending(dest string) []*gpb.SubscribeResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*gpb.SubscribeResponse{}, s.resps[dest]...)
+}
+
+// keep drops the notifications retained for destinations other than dests,
+// which are no longer in use.
+func (s *unackedStore) keep(dests []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for dest := range s.resps {
+		found := false
+		for _, d := range dests {
+			if d == dest {
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.V(1).Infof("Dropping %d unacknowledged notifications of %v", len(s.resps[dest]), dest)
+			delete(s.resps, dest)
+		}
+	}
+}
+
+// ackKey returns the key of the notifications retained for dc. In failover
+// mode the destinations share them, so that the next destination receives
+// those the previous one did not acknowledge.
+func (cs *clientSubscription) ackKey(dc *destConn) string {
+	if cs.dstMode == Failover {
+		return ""
+	}
+	return dc.dest.Addrs
+}
+
+// retain keeps resp sent to dc for retransmission in acknowledged mode.
+func (cs *clientSubscription) retain(dc *destConn, resp *gpb.SubscribeResponse) {
+	if clientCfg.Unidirectional || cs.unacked == nil || resp.GetUpdate() == nil {
+		return
+	}
+	cs.unacked.add(cs.ackKey(dc), resp)
+}
+
+// resendUnacked retransmits to the connected destinations of conns the
+// notifications they did not acknowledge on a previous connection. A
+// destination failing to send is dropped. It fails only when no destination
+// is left.
+func (cs *clientSubscription) resendUnacked(ctx context.Context, conns []*destConn) error {
+	if cs.unacked == nil {
+		return nil
+	}
+	var keys []string
+	for _, dc := range conns {
+		keys = append(keys, cs.ackKey(dc))
+	}
+	cs.unacked.keep(keys)
+
+	var err error
+	live, clients := cs.connected(conns)
+	for i, dc := range live {
+		if e := cs.resendUnackedTo(dc, clients[i]); e != nil {
+			err = e
+			cs.drop(ctx, dc, clients[i], e)
+		}
+	}
+	if live, _ := cs.connected(conns); len(live) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no destination connected for %v", cs.name)
+		}
+		return err
+	}
+	return nil
+}
+
+// resendUnackedTo retransmits with c the notifications dc did not acknowledge.
+func (cs *clientSubscription) resendUnackedTo(dc *destConn, c *Client) error {
+	pending := cs.unacked.pending(cs.ackKey(dc))
+	if len(pending) != 0 {
+		log.V(2).Infof("Client %v retransmitting %d unacknowledged notifications to %v", cs.name, len(pending), dc.dest)
+	}
+	for _, resp := range pending {
+		if err := c.send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publish sends resp to the connected destinations: to all of them, or in
+// hash mode each update and delete to the destination its path hashes to.
+// In acknowledged mode each destination keeps what it was sent until it
+// acknowledges it. A destination failing to send is dropped and, in hash
+// mode, its share is re-hashed over the remaining ones. It fails only when no
+// destination is left.
+func (cs *clientSubscription) publish(ctx context.Context, conns []*destConn, resp *gpb.SubscribeResponse) error {
+	var err error
+	for resp != nil {
+		live, clients := cs.connected(conns)
+		if len(live) == 0 {
+			break
+		}
+		shards := cs.shard(resp, len(live))
+		resp = nil
+		for i, r := range shards {
+			if r == nil {
+				continue
+			}
+			e := clients[i].send(r)
+			if e == nil || cs.dstMode != Hash {
+				cs.retain(live[i], r)
+			}
+			if e != nil {
+				err = e
+				cs.drop(ctx, live[i], clients[i], e)
+				if cs.dstMode == Hash && r.GetUpdate() != nil {
+					resp = mergeResponses(resp, r)
+				}
+			}
+		}
+	}
+	if live, _ := cs.connected(conns); len(live) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no destination connected for %v", cs.name)
+		}
+		return err
+	}
+	cs.sendMsg++
+	return nil
+}
+
+// shard returns the response to send to each of num destinations: resp to
+// all of them, or in hash mode the updates and deletes whose path hashes to
+// the destination, nil if there is none.
+func (cs *clientSubscription) shard(resp *gpb.SubscribeResponse, num int) []*gpb.SubscribeResponse {
+	shards := make([]*gpb.SubscribeResponse, num)
+	n := resp.GetUpdate()
+	if cs.dstMode != Hash || n == nil || num == 1 {
+		for i := range shards {
+			shards[i] = resp
+		}
+		return shards
+	}
+
+	notifs := make([]*gpb.Notification, num)
+	shard := func(path *gpb.Path) *gpb.Notification {
+		i := pathShard(n.GetPrefix(), path, num)
+		if notifs[i] == nil {
+			notifs[i] = &gpb.Notification{
+				Timestamp: n.GetTimestamp(),
+				Prefix:    n.GetPrefix(),
+				Alias:     n.GetAlias(),
+				Atomic:    n.GetAtomic(),
+			}
+		}
+		return notifs[i]
+	}
+	for _, u := range n.GetUpdate() {
+		sn := shard(u.GetPath())
+		sn.Update = append(sn.Update, u)
+	}
+	for _, d := range n.GetDelete() {
+		sn := shard(d)
+		sn.Delete = append(sn.Delete, d)
+	}
+	for i, sn := range notifs {
+		if sn != nil {
+			shards[i] = &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: sn}}
+		}
+	}
+	return shards
+}
+
+// mergeResponses returns the shards a and b of the same notification as one,
+// a may be nil.
+func mergeResponses(a, b *gpb.SubscribeResponse) *gpb.SubscribeResponse {
+	if a == nil {
+		return b
+	}
+	n, m := a.GetUpdate(), b.GetUpdate()
+	return &gpb.SubscribeResponse{
+		Response: &gpb.SubscribeResponse_Update{
+			Update: &gpb.Notification{
+				Timestamp: n.GetTimestamp(),
+				Prefix:    n.GetPrefix(),
+				Alias:     n.GetAlias(),
+				Update:    append(append([]*gpb.Update{}, n.GetUpdate()...), m.GetUpdate()...),
+				Delete:    append(append([]*gpb.Path{}, n.GetDelete()...), m.GetDelete()...),
+				Atomic:    n.GetAtomic(),
+			},
+		},
+	}
+}
+
+// connected returns the destinations of conns currently connected, with
+// their clients.
+func (cs *clientSubscription) connected(conns []*destConn) ([]*destConn, []*Client) {
+	cs.cMu.Lock()
+	defer cs.cMu.Unlock()
+	var live []*destConn
+	var clients []*Client
+	for _, dc := range conns {
+		if dc.client != nil {
+			live = append(live, dc)
+			clients = append(clients, dc.client)
+		}
+	}
+	return live, clients
+}
+
+// drop disconnects dc after its client c failed to send. In broadcast and
+// hash modes dc is reconnected in the background, in failover mode
+// publishRun moves on to the next destination once none is left.
+func (cs *clientSubscription) drop(ctx context.Context, dc *destConn, c *Client, err error) {
+	cs.cMu.Lock()
+	defer cs.cMu.Unlock()
+	cs.errors++
+	if dc.client != c {
+		return
+	}
+	log.V(1).Infof("Client %v dropped destination %v: %v", cs.name, dc.dest, err)
+	dc.client = nil
+	c.Close()
+	if cs.dstMode != Failover && cs.opened {
+		go cs.reconnect(ctx, dc, cs.stop)
+	}
+}
+
+// reconnect connects dc again with exponential backoff, until it succeeds or
+// the instance of the client subscription started with stop ends. The
+// destination is sent again the notifications it did not acknowledge, then
+// those published from then on.
+func (cs *clientSubscription) reconnect(ctx context.Context, dc *destConn, stop chan struct{}) {
+	for failures := uint(0); ; failures++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-time.After(retryBackoff(failures)):
+		}
+		c, err := dial(ctx, dc.dest)
+		if err != nil {
+			log.V(1).Infof("Dialout reconnection to %v for %v failed: %v", dc.dest, cs.name, err)
+			continue
+		}
+		if !clientCfg.Unidirectional && cs.unacked != nil {
+			if err = cs.resendUnackedTo(dc, c); err != nil {
+				log.V(1).Infof("Dialout retransmission to %v for %v failed: %v", dc.dest, cs.name, err)
+				c.Close()
+				continue
+			}
+		}
+		if !cs.attach(dc, c, stop) {
+			c.Close()
+			return
+		}
+		log.V(1).Infof("Dialout service reconnected to %v for %v", dc.dest, cs.name)
+		return
+	}
+}
+
+// attach makes c the client of dc if the instance of the client subscription
+// started with stop is still running, and receives its acknowledgements.
+func (cs *clientSubscription) attach(dc *destConn, c *Client, stop chan struct{}) bool {
+	cs.cMu.Lock()
+	defer cs.cMu.Unlock()
+	if !cs.opened || cs.stop != stop {
+		return false
+	}
+	dc.client = c
+	cs.recvAcksOf(dc)
+	return true
+}
+
+// recvAcksOf starts receiving the acknowledgements of the client of dc in
+// acknowledged mode.
+func (cs *clientSubscription) recvAcksOf(dc *destConn) {
+	dc.acksDone = nil
+	if clientCfg.Unidirectional {
+		return
+	}
+	dc.acksDone = make(chan struct{})
+	go cs.recvAcks(dc.client.publish, cs.ackKey(dc), dc.acksDone)
+}
+
+// pathShard returns the index of the destination in charge of path.
+func pathShard(prefix *gpb.Path, path *gpb.Path, num int) int {
+	h := fnv.New32a()
+	for _, p := range []*gpb.Path{prefix, path} {
+		s, err := ygot.PathToString(p)
+		if err != nil {
+			s = p.String()
+		}
+		h.Write([]byte(s))
+	}
+	return int(h.Sum32() % uint32(num))
+}
+
+// retryBackoff returns the delay before the connection attempt following
+// the given number of consecutive failures: it doubles from minRetryBackoff
+// up to the retry interval, with random jitter.
+func retryBackoff(failures uint) time.Duration {
+	max := clientCfg.RetryInterval
+	if max < minRetryBackoff {
+		max = minRetryBackoff
+	}
+	d := max
+	if failures < 32 && minRetryBackoff<<failures < max {
+		d = minRetryBackoff << failures
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// recvAcks processes the PublishResponse messages from the remote server
+// of the notifications retained under key until the stream is closed, then
+// closes done.
+func (cs *clientSubscription) recvAcks(stream spb.GNMIDialOut_PublishClient, key string, done chan struct{}) {
+	defer close(done)
+	for {
+		pr, err := stream.Recv()
+		if err != nil {
+			log.V(2).Infof("Client %v stopped receiving acknowledgements: %v", cs.name, err)
+			return
+		}
+		atomic.AddUint64(&cs.recvMsg, 1)
+		n := cs.unacked.ack(key, pr)
+		log.V(5).Infof("Client %v acknowledged %d notifications by %v", cs.name, n, pr)
+	}
+}
+
+// ParseEncoding returns the gNMI encoding named by s, e.g. "JSON_IETF" or "PROTO".
+func ParseEncoding(s string) (gpb.Encoding, error) {
+	v, ok := gpb.Encoding_value[strings.ToUpper(strings.TrimSpace(s))]
+	if !ok {
+		return gpb.Encoding_JSON_IETF, fmt.Errorf("unsupported encoding %q", s)
+	}
+	return gpb.Encoding(v), nil
+}
+
+// encodeResponse re-encodes the JSON_IETF values of a notification with the
+// configured encoding.
+func encodeResponse(resp *gpb.SubscribeResponse, encoding gpb.Encoding) (*gpb.SubscribeResponse, error) {
+	n := resp.GetUpdate()
+	if n == nil || encoding == gpb.Encoding_JSON_IETF {
+		return resp, nil
+	}
+	updates, err := encodeUpdates(n.GetUpdate(), encoding)
+	if err != nil {
+		return nil, err
+	}
+	return &gpb.SubscribeResponse{
+		Response: &gpb.SubscribeResponse_Update{
+			Update: &gpb.Notification{
+				Timestamp: n.GetTimestamp(),
+				Prefix:    n.GetPrefix(),
+				Alias:     n.GetAlias(),
+				Update:    updates,
+				Delete:    n.GetDelete(),
+				Atomic:    n.GetAtomic(),
+			},
+		},
+	}, nil
+}
+
+// encodeUpdates converts the JSON_IETF values produced by the SONiC data
+// clients to the given encoding. With PROTO encoding each JSON leaf becomes
+// its own update with a scalar value, its path extended by the JSON members.
+func encodeUpdates(updates []*gpb.Update, encoding gpb.Encoding) ([]*gpb.Update, error) {
+	var encoded []*gpb.Update
+	for _, u := range updates {
+		jv := u.GetVal().GetJsonIetfVal()
+		if jv == nil {
+			encoded = append(encoded, u)
+			continue
+		}
+		switch encoding {
+		case gpb.Encoding_JSON_IETF:
+			encoded = append(encoded, u)
+		case gpb.Encoding_JSON:
+			encoded = append(encoded, &gpb.Update{Path: u.GetPath(),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_JsonVal{JsonVal: jv}}})
+		case gpb.Encoding_BYTES:
+			encoded = append(encoded, &gpb.Update{Path: u.GetPath(),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_BytesVal{BytesVal: jv}}})
+		case gpb.Encoding_ASCII:
+			encoded = append(encoded, &gpb.Update{Path: u.GetPath(),
+				Val: &gpb.TypedValue{Value: &gpb.TypedValue_AsciiVal{AsciiVal: string(jv)}}})
+		case gpb.Encoding_PROTO:
+			var v interface{}
+			d := json.NewDecoder(bytes.NewReader(jv))
+			d.UseNumber()
+			if err := d.Decode(&v); err != nil {
+				return nil, fmt.Errorf("invalid JSON value for %v: %v", u.GetPath(), err)
+			}
+			leaves, err := jsonToScalarUpdates(u.GetPath(), v)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, leaves...)
+		default:
+			return nil, fmt.Errorf("unsupported encoding %v", encoding)
+		}
+	}
+	return encoded, nil
+}
+
+// jsonToScalarUpdates flattens a decoded JSON value into updates with scalar
+// TypedValues, sorted by path.
+func jsonToScalarUpdates(path *gpb.Path, v interface{}) ([]*gpb.Update, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var updates []*gpb.Update
+		for _, name := range names {
+			child := &gpb.Path{
+				Origin: path.GetOrigin(),
+				Target: path.GetTarget(),
+				Elem:   append(append([]*gpb.PathElem{}, path.GetElem()...), &gpb.PathElem{Name: name}),
+			}
+			leaves, err := jsonToScalarUpdates(child, m[name])
+			if err != nil {
+				return nil, err
+			}
+			updates = append(updates, leaves...)
+		}
+		return updates, nil
+	}
+	if v == nil {
+		return nil, nil
+	}
+	tv, err := jsonToScalar(v)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return []*gpb.Update{{Path: path, Val: tv}}, nil
+}
+
+func jsonToScalar(v interface{}) (*gpb.TypedValue, error) {
+	switch val := v.(type) {
+	case string:
+		return &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: val}}, nil
+	case bool:
+		return &gpb.TypedValue{Value: &gpb.TypedValue_BoolVal{BoolVal: val}}, nil
+	case json.Number:
+		if i, err := strconv.ParseInt(string(val), 10, 64); err == nil {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_IntVal{IntVal: i}}, nil
+		}
+		if u, err := strconv.ParseUint(string(val), 10, 64); err == nil {
+			return &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: u}}, nil
+		}
+		d, err := jsonToDecimal(val)
+		if err != nil {
+			return nil, err
+		}
+		return &gpb.TypedValue{Value: &gpb.TypedValue_DecimalVal{DecimalVal: d}}, nil
+	case []interface{}:
+		list := &gpb.ScalarArray{}
+		for _, e := range val {
+			tv, err := jsonToScalar(e)
+			if err != nil {
+				return nil, err
+			}
+			list.Element = append(list.Element, tv)
+		}
+		return &gpb.TypedValue{Value: &gpb.TypedValue_LeaflistVal{LeaflistVal: list}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value %v of type %T", v, v)
+	}
+}
+
+// Largest number of fraction digits of a Decimal64
+const maxDecimalPrecision = 18
+
+// jsonToDecimal returns the JSON number n as a Decimal64. The gNMI version
+// in use has no double_val, a decimal keeps the value as written instead of
+// rounding it to a float: fraction digits beyond the int64 range of the
+// digits, or beyond maxDecimalPrecision, are truncated.
+func jsonToDecimal(n json.Number) (*gpb.Decimal64, error) {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return nil, fmt.Errorf("invalid number %v", n)
+	}
+	var d *gpb.Decimal64
+	scale := big.NewInt(1)
+	for prec := uint32(0); prec <= maxDecimalPrecision; prec++ {
+		scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+		digits := new(big.Int).Quo(scaled.Num(), scaled.Denom())
+		if !digits.IsInt64() {
+			break
+		}
+		d = &gpb.Decimal64{Digits: digits.Int64(), Precision: prec}
+		if scaled.IsInt() {
+			break
+		}
+		scale.Mul(scale, big.NewInt(10))
+	}
+	if d == nil {
+		return nil, fmt.Errorf("number %v out of decimal64 range", n)
+	}
+	return d, nil
+}
+
 func (cs *clientSubscription) Close() {
 	cs.cMu.Lock()
 	defer cs.cMu.Unlock()
@@ -161,9 +935,10 @@ func (cs *clientSubscription) Close() {
 			cs.q.Dispose()
 		}
 	}
-	if cs.client != nil {
-
-		cs.client.Close() // Close GNMIDialOutClient
+	for _, dc := range cs.conns {
+		if dc.client != nil {
+			dc.client.Close() // Close GNMIDialOutClient
+		}
 	}
 	cs.opened = false
 	log.V(2).Infof("Closed %v", cs)
@@ -183,20 +958,14 @@ func (cs *clientSubscription) NewInstance(ctx context.Context) error {
 		log.V(2).Infof("Destination group %v doesn't exist", cs.destGroupName)
 		return fmt.Errorf("Destination group %v doesn't exist", cs.destGroupName)
 	}
+	cs.dstMode = destGrpModeMap[cs.destGroupName]
 
-	target := cs.prefix.GetTarget()
-	if target == "" {
-		return fmt.Errorf("Empty target data not supported yet")
+	if cs.prefix == nil {
+		cs.prefix = &gpb.Path{}
 	}
 
-	// Connection to system data source
-	var dc sdc.Client
-	var err error
-	if target == "OTHERS" {
-		dc, err = sdc.NewNonDbClient(cs.paths, cs.prefix)
-	} else {
-		dc, err = sdc.NewDbClient(cs.paths, cs.prefix)
-	}
+	// Connection to system data source, selected by origin and target as for gNMI Subscribe
+	dc, err := sdc.NewSubscribeClient(ctx, cs.prefix, cs.paths, cs.origin, nil, eventsLogLevel)
 	if err != nil {
 		log.V(1).Infof("Connection to DB for %v failed: %v", *cs, err)
 		return fmt.Errorf("Connection to DB for %v failed: %v", *cs, err)
@@ -208,7 +977,7 @@ func (cs *clientSubscription) NewInstance(ctx context.Context) error {
 }
 
 // send runs until process Queue returns an error.
-func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
+func (cs *clientSubscription) send(ctx context.Context, conns []*destConn) error {
 	for {
 		items, err := cs.q.Get(1)
 
@@ -225,7 +994,11 @@ func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
 		var resp *gpb.SubscribeResponse
 		switch v := items[0].(type) {
 		case sdc.Value:
-			if resp, err = sdc.ValToResp(v); err != nil {
+			if resp, err = sdc.ValToResp(v, nil); err != nil {
+				cs.errors++
+				return err
+			}
+			if resp, err = encodeResponse(resp, clientCfg.Encoding); err != nil {
 				cs.errors++
 				return err
 			}
@@ -234,11 +1007,9 @@ func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
 			cs.errors++
 		}
 
-		cs.sendMsg++
-		err = stream.Send(resp)
+		err = cs.publish(ctx, conns, resp)
 		if err != nil {
 			log.V(1).Infof("Client %s sending error:%v", cs, err)
-			cs.errors++
 			return err
 		}
 		log.V(5).Infof("Client %s done sending, msg count %d, msg %v", cs, cs.sendMsg, resp)
@@ -248,12 +1019,11 @@ func (cs *clientSubscription) send(stream spb.GNMIDialOut_PublishClient) error {
 // String returns the target the client is querying.
 func (cs *clientSubscription) String() string {
 	return fmt.Sprintf(" %s:%s:%s prefix %v paths %v interval %v, sendMsg %v, recvMsg %v",
-		cs.name, cs.destGroupName, cs.reportType, cs.prefix.GetTarget(), cs.paths, cs.interval, cs.sendMsg, cs.recvMsg)
+		cs.name, cs.destGroupName, cs.reportType, cs.prefix.GetTarget(), cs.paths, cs.interval, cs.sendMsg, atomic.LoadUint64(&cs.recvMsg))
 }
 
 // newClient returns a new initialized GNMIDialout client.
 // it connects to destination and publish service
-// TODO: TLS credential support
 func newClient(ctx context.Context, dest Destination) (*Client, error) {
 	timeout := clientCfg.RetryInterval
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -262,8 +1032,15 @@ func newClient(ctx context.Context, dest Destination) (*Client, error) {
 	opts := []grpc.DialOption{
 		grpc.WithBlock(),
 	}
-	if clientCfg.TLS != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(clientCfg.TLS)))
+	tlsCfg := clientCfg.TLS
+	if dest.TLS != nil {
+		var err error
+		if tlsCfg, err = dest.TLS.config(); err != nil {
+			return nil, fmt.Errorf("TLS config for %s: %v", dest, err)
+		}
+	}
+	if tlsCfg != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	}
 	conn, err := grpc.DialContext(ctx, dest.Addrs, opts...)
 	if err != nil {
@@ -280,105 +1057,165 @@ func newClient(ctx context.Context, dest Destination) (*Client, error) {
 // or fatal error of any client go routine .
 // it will cause cancle of client context and exit of the send goroutines.
 func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
 	return c.conn.Close()
 }
 
+// send publishes resp on the Publish stream of the client.
+func (c *Client) send(resp *gpb.SubscribeResponse) error {
+	if err := c.publish.Send(resp); err != nil {
+		return err
+	}
+	c.sendMsg++
+	return nil
+}
+
+// closeSend half closes the Publish stream and waits for the remote server
+// to end it. done, if not nil, is closed by the acknowledgement receiver.
+func (c *Client) closeSend(done chan struct{}) {
+	c.publish.CloseSend()
+	if done != nil {
+		<-done
+		return
+	}
+	for {
+		if _, err := c.publish.Recv(); err != nil {
+			return
+		}
+	}
+}
+
+// dial connects to the destination and opens its Publish stream.
+func dial(ctx context.Context, dest Destination) (*Client, error) {
+	c, err := newClient(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	log.V(1).Infof("Dialout service connected to %v successfully", dest)
+	if c.publish, err = c.client.Publish(ctx); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("Publish to %s: %v", dest, err)
+	}
+	return c, nil
+}
+
 func publishRun(ctx context.Context, cs *clientSubscription, dests []Destination) {
 	var err error
-	var c *Client
+	var conns []*destConn
 	var destNum, destIdx int
+	var failures uint // consecutive failures to connect to any destination, for backoff
 	destNum = len(dests)
 	destIdx = 0
 
 restart: //Remote server might go down, in that case we restart with next destination in the group
+	if failures > 0 {
+		select {
+		case <-ctx.Done():
+			cs.Close()
+			log.V(1).Infof("%v: %v, cs.conTryCnt %v", cs, ctx.Err(), cs.conTryCnt)
+			return
+		case <-time.After(retryBackoff(failures - 1)):
+		}
+	}
+	failures++
+
 	cs.cMu.Lock()
 	cs.stop = make(chan struct{}, 1)
 	cs.q = queue.NewPriorityQueue(1, false)
 	cs.opened = true
-	cs.client = nil
+	cs.conns = nil
+	stop := cs.stop
 	cs.cMu.Unlock()
 
 	cs.conTryCnt++
-	dest := dests[destIdx]
-	destIdx = (destIdx + 1) % destNum
-	c, err = newClient(ctx, dest)
-	select {
-	case <-ctx.Done():
-		cs.Close()
-		log.V(1).Infof("%v: %v, cs.conTryCnt %v", cs, err, cs.conTryCnt)
-		return
-	default:
+	// In failover mode only one destination is used at a time, otherwise all
+	targets := dests
+	if cs.dstMode == Failover {
+		targets = []Destination{dests[destIdx]}
+		destIdx = (destIdx + 1) % destNum
 	}
-	if err != nil {
-		log.V(1).Infof("Dialout connection for %v failed for %v, %v cs.conTryCnt %v", dest, cs.name, err, cs.conTryCnt)
-		goto restart
+	// In broadcast and hash modes the destinations failing to connect are
+	// retried in the background, as long as one of them is connected.
+	conns = nil
+	connected := 0
+	for _, dest := range targets {
+		dc := &destConn{dest: dest}
+		dc.client, err = dial(ctx, dest)
+		select {
+		case <-ctx.Done():
+			closeConns(conns)
+			cs.Close()
+			log.V(1).Infof("%v: %v, cs.conTryCnt %v", cs, err, cs.conTryCnt)
+			return
+		default:
+		}
+		if err != nil {
+			log.V(1).Infof("Dialout connection for %v failed for %v, %v cs.conTryCnt %v", dest, cs.name, err, cs.conTryCnt)
+		} else {
+			connected++
+		}
+		conns = append(conns, dc)
 	}
-
-	log.V(1).Infof("Dialout service connected to %v successfully for %v", dest, cs.name)
-	pub, err := c.client.Publish(ctx)
-	if err != nil {
-		log.V(1).Infof("Publish to %v for %v failed: %v, retrying", dest, cs.name, err)
-		c.Close()
+	if connected == 0 {
 		cs.Close()
 		goto restart
 	}
 
 	cs.cMu.Lock()
-	if cs.client == nil {
-		cs.client = c
+	if cs.conns == nil {
+		cs.conns = conns
 	} else {
-		log.V(1).Infof("connection to %v already exists for %v, exiting publishRun", dest, cs)
-		c.Close()
+		log.V(1).Infof("connection to %v already exists for %v, exiting publishRun", targets, cs)
+		closeConns(conns)
 		cs.cMu.Unlock()
 		return
 	}
+	for _, dc := range conns {
+		if dc.client != nil {
+			cs.recvAcksOf(dc)
+		} else {
+			go cs.reconnect(ctx, dc, stop)
+		}
+	}
 	cs.cMu.Unlock()
 
+	if !clientCfg.Unidirectional {
+		if err = cs.resendUnacked(ctx, conns); err != nil {
+			log.V(1).Infof("Client %v retransmission error:%v, cs.conTryCnt %v", cs.name, err, cs.conTryCnt)
+			cs.Close()
+			goto restart
+		}
+	}
+	failures = 0
+
 	switch cs.reportType {
 	case Periodic:
 		for {
 			select {
 			default:
-				spbValues, err := cs.dc.Get(nil)
+				response, err := cs.snapshot()
 				if err != nil {
 					// TODO: need to inform
 					log.V(2).Infof("Data read error %v for %v", err, cs)
 					continue
 					//return nil, status.Error(codes.NotFound, err.Error())
 				}
-				var updates []*gpb.Update
-				var spbValue *spb.Value
-				for _, spbValue = range spbValues {
-					update := &gpb.Update{
-						Path: spbValue.GetPath(),
-						Val:  spbValue.GetVal(),
-					}
-					updates = append(updates, update)
-				}
-				rs := &gpb.SubscribeResponse_Update{
-					Update: &gpb.Notification{
-						Timestamp: spbValue.GetTimestamp(),
-						Prefix:    cs.prefix,
-						Update:    updates,
-					},
-				}
-				response := &gpb.SubscribeResponse{Response: rs}
 
-				log.V(6).Infof("cs %s sending \n\t%v \n To %s", cs.name, response, dest)
-				err = pub.Send(response)
+				log.V(6).Infof("cs %s sending \n\t%v \n To %v", cs.name, response, targets)
+				err = cs.publish(ctx, conns, response)
 				if err != nil {
 					log.V(1).Infof("Client %v pub Send error:%v, cs.conTryCnt %v", cs.name, err, cs.conTryCnt)
 					cs.Close()
 					// Retry
 					goto restart
 				}
-				log.V(6).Infof("cs %s to  %s done", cs.name, dest)
-				cs.sendMsg++
-				c.sendMsg++
+				log.V(6).Infof("cs %s to  %v done", cs.name, targets)
 
 				time.Sleep(cs.interval)
 			case <-cs.stop:
-				log.V(1).Infof("%v exiting publishRun routine for destination %s", cs, dest)
+				log.V(1).Infof("%v exiting publishRun routine for destination %v", cs, targets)
 				return
 			}
 		}
@@ -386,27 +1223,106 @@ restart: //Remote server might go down, in that case we restart with next destin
 		select {
 		default:
 			cs.w.Add(1)
-			go cs.dc.StreamRun(cs.q, cs.stop, &cs.w, nil)
+			go cs.dc.StreamRun(cs.q, cs.stop, &cs.w, cs.streamSubscription())
 			time.Sleep(100 * time.Millisecond)
-			err = cs.send(pub)
+			err = cs.send(ctx, conns)
 			if err != nil {
 				log.V(1).Infof("Client %v pub Send error:%v, cs.conTryCnt %v", cs.name, err, cs.conTryCnt)
 			}
 			cs.Close()
 			cs.w.Wait()
 			// Don't restart immediatly
-			time.Sleep(clientCfg.RetryInterval)
+			failures++
 			goto restart
 
 		case <-cs.stop:
-			log.V(1).Infof("%v exiting publishRun routine for destination %s", cs, dest)
+			log.V(1).Infof("%v exiting publishRun routine for destination %v", cs, targets)
 			return
 		}
+	case Once:
+		response, err := cs.snapshot()
+		if err != nil {
+			log.V(1).Infof("Data read error %v for %v, exiting publishRun", err, cs)
+			cs.Close()
+			return
+		}
+		syncResp := &gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}}
+		for _, resp := range []*gpb.SubscribeResponse{response, syncResp} {
+			if err = cs.publish(ctx, conns, resp); err != nil {
+				log.V(1).Infof("Client %v pub Send error:%v, cs.conTryCnt %v", cs.name, err, cs.conTryCnt)
+				cs.Close()
+				// Retry
+				goto restart
+			}
+		}
+		// Half close the streams and wait for the remote servers to end them, so
+		// that the snapshot is fully delivered before the connections are closed.
+		live, clients := cs.connected(conns)
+		for i, c := range clients {
+			c.closeSend(live[i].acksDone)
+		}
+		log.V(1).Infof("%v published once to destination %v", cs, targets)
+		cs.Close()
 	default:
 		log.V(1).Infof("Unsupported report type %s in %v ", cs.reportType, cs)
 	}
 }
 
+func closeConns(conns []*destConn) {
+	for _, dc := range conns {
+		if dc.client != nil {
+			dc.client.Close()
+		}
+	}
+}
+
+// streamSubscription returns the SubscriptionList given to StreamRun. The
+// SONiC DB clients stream all paths ON_CHANGE without one, as dial-out always
+// did; translib needs the subscribed paths.
+func (cs *clientSubscription) streamSubscription() *gpb.SubscriptionList {
+	if _, ok := cs.dc.(*sdc.TranslClient); !ok {
+		return nil
+	}
+	list := &gpb.SubscriptionList{
+		Prefix:   cs.prefix,
+		Mode:     gpb.SubscriptionList_STREAM,
+		Encoding: gpb.Encoding_JSON_IETF,
+	}
+	for _, p := range cs.paths {
+		list.Subscription = append(list.Subscription, &gpb.Subscription{
+			Path: p,
+			Mode: gpb.SubscriptionMode_TARGET_DEFINED,
+		})
+	}
+	return list
+}
+
+// snapshot reads the current data of the client subscription paths as a
+// single notification.
+func (cs *clientSubscription) snapshot() (*gpb.SubscribeResponse, error) {
+	spbValues, err := cs.dc.Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	var updates []*gpb.Update
+	var spbValue *spb.Value
+	for _, spbValue = range spbValues {
+		update := &gpb.Update{
+			Path: spbValue.GetPath(),
+			Val:  spbValue.GetVal(),
+		}
+		updates = append(updates, update)
+	}
+	rs := &gpb.SubscribeResponse_Update{
+		Update: &gpb.Notification{
+			Timestamp: spbValue.GetTimestamp(),
+			Prefix:    cs.prefix,
+			Update:    updates,
+		},
+	}
+	return encodeResponse(&gpb.SubscribeResponse{Response: rs}, clientCfg.Encoding)
+}
+
 /*
 	// telemetry client  global configuration
 	Key         = TELEMETRY_CLIENT|Global
@@ -418,19 +1334,87 @@ restart: //Remote server might go down, in that case we restart with next destin
 	// Destination group
 	Key      = TELEMETRY_CLIENT|DestinationGroup_<name>
 	dst_addr   = IP1:PORT2,IP2:PORT2       ;IP addresses separated by ","
+	dst_mode   = "failover" / "broadcast" / "hash"  ;failover by default
+	ca_crt     = path                      ;CA bundle to validate the server certificates, optional
+	client_crt = path                      ;client certificate, optional
+	client_key = path                      ;client private key, optional
+	server_name = hostname                 ;server name override for validation, optional
+	insecure   = "true" / "false"          ;skip server certificate validation, false by default
 
 	PORT = 1*5DIGIT
 	IP = dec-octet "." dec-octet "." dec-octet "." dec-octet
 
 	// Subscription group
 	Key         = TELEMETRY_CLIENT|Subscription_<name>
-	path_target = DbName
+	origin      = "openconfig" / "sonic-db"  ; optional, paths are selected by path_target if empty
+	path_target = DbName / "OTHERS" / "EVENTS"
 	paths       = PATH1,PATH2        ;PATH separated by ","
 	dst_group   = <name>      ; // name of DestinationGroup
 	report_type = "periodic" / "stream" / "once"
 	report_interval = 1*8DIGIT      ; In millisecond,
 */
 
+// refreshDestGroupTLS reloads the TLS config of the destination groups whose
+// certificate files changed, and reconnects their client instances. A config
+// already reloaded by a connection attempt also reconnects the other clients.
+func refreshDestGroupTLS(ctx context.Context) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	for destGroupName, dests := range destGrpNameMap {
+		if len(dests) == 0 || dests[0].TLS == nil {
+			continue
+		}
+		dt := dests[0].TLS
+		if _, err := dt.refresh(); err != nil {
+			log.V(1).Infof("TLS config reload for %v failed: %v", destGroupName, err)
+			continue
+		}
+		if gen := dt.generation(); gen != dt.appliedGen {
+			dt.appliedGen = gen
+			log.V(1).Infof("TLS config of %v changed, reconnecting", destGroupName)
+			closeDestGroupClient(destGroupName)
+			setupDestGroupClients(ctx, destGroupName)
+		}
+	}
+}
+
+// watchDestGroupTLSFiles adds the directories of the certificate files of
+// the destination groups to watcher. The directories are watched, as tools
+// rotating certificates replace the files.
+func watchDestGroupTLSFiles(watcher *fsnotify.Watcher) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	for destGroupName, dests := range destGrpNameMap {
+		if len(dests) == 0 || dests[0].TLS == nil {
+			continue
+		}
+		for _, f := range dests[0].TLS.files() {
+			if err := watcher.Add(filepath.Dir(f)); err != nil {
+				log.V(1).Infof("Watching TLS file %v of %v failed: %v", f, destGroupName, err)
+			}
+		}
+	}
+}
+
+// watchDestGroupTLS refreshes the TLS config of the destination groups upon
+// changes in the directories watched by watcher, until ctx is done.
+func watchDestGroupTLS(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+	for {
+		select {
+		case event := <-watcher.Events:
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				log.V(2).Infof("TLS file %v changed", event.Name)
+				refreshDestGroupTLS(ctx)
+			}
+		case err := <-watcher.Errors:
+			log.V(1).Infof("Error watching TLS files: %v", err)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // closeDestGroupClient close client instances for all clientSubscription using
 // this Destination Group
 func closeDestGroupClient(destGroupName string) {
@@ -496,11 +1480,19 @@ func processTelemetryClientConfig(ctx context.Context, redisDb *redis.Client, ke
 					}
 					clientCfg.RetryInterval = time.Second * time.Duration(itvl)
 				case "encoding":
-					//Flexible encoding Not supported yet
-					clientCfg.Encoding = gpb.Encoding_JSON_IETF
+					encoding, err := ParseEncoding(value)
+					if err != nil {
+						log.V(2).Infof("Invalid encoding %v %v", value, err)
+						continue
+					}
+					clientCfg.Encoding = encoding
 				case "unidirectional":
-					// No PublishResponse supported yet
-					clientCfg.Unidirectional = true
+					unidirectional, err := strconv.ParseBool(value)
+					if err != nil {
+						log.V(2).Infof("Invalid unidirectional %v %v", value, err)
+						continue
+					}
+					clientCfg.Unidirectional = unidirectional
 				}
 			}
 			// Apply changes to all running instances
@@ -523,10 +1515,14 @@ func processTelemetryClientConfig(ctx context.Context, redisDb *redis.Client, ke
 				return fmt.Errorf("%v is being used: %v", destGroupName, DestGrp2ClientSubMap)
 			}
 			delete(destGrpNameMap, destGroupName)
+			delete(destGrpModeMap, destGroupName)
 			log.V(3).Infof("Deleted  DestinationGroup %v", destGroupName)
 			return nil
 		} else {
 			var dests []Destination
+			mode := Failover
+			var dstTLS DestinationTLS
+			tlsSet := false
 			for field, value := range fv {
 				switch field {
 				case "dst_addr":
@@ -539,12 +1535,48 @@ func processTelemetryClientConfig(ctx context.Context, redisDb *redis.Client, ke
 						}
 						dests = append(dests, Destination{Addrs: addr})
 					}
+				case "dst_mode":
+					if mode, err = NewDstMode(value); err != nil {
+						log.V(2).Infof("Invalid dst_mode %v %v", value, err)
+						return fmt.Errorf("Invalid dst_mode %v %v", value, err)
+					}
+				case "ca_crt":
+					dstTLS.CaCrt = value
+					tlsSet = true
+				case "client_crt":
+					dstTLS.ClientCrt = value
+					tlsSet = true
+				case "client_key":
+					dstTLS.ClientKey = value
+					tlsSet = true
+				case "server_name":
+					dstTLS.ServerName = value
+					tlsSet = true
+				case "insecure":
+					insecure, err := strconv.ParseBool(value)
+					if err != nil {
+						log.V(2).Infof("Invalid insecure %v %v", value, err)
+						return fmt.Errorf("Invalid insecure %v %v", value, err)
+					}
+					dstTLS.Insecure = insecure
+					tlsSet = true
 				default:
 					log.V(2).Infof("Invalid DestinationGroup value %v", value)
 					return fmt.Errorf("Invalid DestinationGroup value %v", value)
 				}
 			}
+			if tlsSet {
+				if _, err = dstTLS.refresh(); err != nil {
+					log.V(2).Infof("Invalid TLS config for %v: %v", destGroupName, err)
+					return fmt.Errorf("Invalid TLS config for %v: %v", destGroupName, err)
+				}
+				dstTLS.appliedGen = dstTLS.generation()
+				for i := range dests {
+					dests[i].TLS = &dstTLS
+				}
+			}
 			destGrpNameMap[destGroupName] = dests
+			destGrpModeMap[destGroupName] = mode
 			setupDestGroupClients(ctx, destGroupName)
 		}
 	} else if strings.HasPrefix(key, "Subscription_") {
@@ -580,6 +1612,11 @@ func processTelemetryClientConfig(ctx context.Context, redisDb *redis.Client, ke
 				interval: 5000, // default to 5000 milliseconds
 				name:     name,
 				cancel:   cancel,
+				unacked:  newUnackedStore(),
+			}
+			if ok {
+				// Keep unacknowledged notifications across subscription updates
+				cs.unacked = csub.unacked
 			}
 			for field, value := range fv {
 				switch field {
@@ -594,6 +1631,8 @@ func processTelemetryClientConfig(ctx context.Context, redisDb *redis.Client, ke
 						continue
 					}
 					cs.interval = time.Duration(intvl) * time.Millisecond
+				case "origin":
+					cs.origin = value
 				case "path_target":
 					cs.prefix = &gpb.Path{
 						Target: value,
@@ -712,12 +1751,23 @@ func DialOutRun(ctx context.Context, ccfg *ClientConfig) error {
 		processTelemetryClientConfig(ctx, redisDb, dbkey, "hset")
 	}
 
+	// Certificate changes are also picked up on the pubsub idle timeout and
+	// on each connection attempt, should the watcher fail.
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.V(1).Infof("Failed to create TLS file watcher: %v", err)
+	} else {
+		watchDestGroupTLSFiles(watcher)
+		go watchDestGroupTLS(ctx, watcher)
+	}
+
 	for {
 		msgi, err := pubsub.ReceiveTimeout(time.Millisecond * 1000)
 		if err != nil {
 			neterr, ok := err.(net.Error)
 			if ok {
 				if neterr.Timeout() == true {
+					refreshDestGroupTLS(ctx)
 					continue
 				}
 			}
@@ -734,6 +1784,9 @@ func DialOutRun(ctx context.Context, ccfg *ClientConfig) error {
 			log.V(2).Infof("Invalid psubscribe payload notification:  %v", subscr)
 			continue
 		}
+		if watcher != nil {
+			watchDestGroupTLSFiles(watcher)
+		}
 		// Check if ctx was canceled.
 		select {
 		case <-ctx.Done():
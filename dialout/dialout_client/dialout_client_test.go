@@ -4,10 +4,13 @@ package telemetry_dialout
 // Prerequisite: redis-server should be running.
 
 import (
+	"crypto/rsa"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"github.com/go-redis/redis"
-	//"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/proto"
 	testcert "github.com/sonic-net/sonic-gnmi/testdata/tls"
 
 	//"github.com/kylelemons/godebug/pretty"
@@ -20,6 +23,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	//"google.golang.org/grpc/status"
 	//"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -29,6 +33,7 @@ import (
 	"time"
 
 	sds "github.com/sonic-net/sonic-gnmi/dialout/dialout_server"
+	spb "github.com/sonic-net/sonic-gnmi/proto"
 	sdc "github.com/sonic-net/sonic-gnmi/sonic_data_client"
 	sdcfg "github.com/sonic-net/sonic-gnmi/sonic_db_config"
 	gclient "github.com/openconfig/gnmi/client/gnmi"
@@ -520,6 +525,543 @@ func TestGNMIDialOutPublish(t *testing.T) {
 
 }
 
+func TestGNMIDialOutPublishAck(t *testing.T) {
+	s := createServer(t, &sds.Config{Port: 8082, Ack: true})
+	var store []*pb.SubscribeResponse
+	s.SetDataStore(&store)
+	go runServer(t, s)
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "127.0.0.1:8082", grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	if err != nil {
+		t.Fatalf("Dial to dialout server failed: %v", err)
+	}
+	defer conn.Close()
+	pub, err := spb.NewGNMIDialOutClient(conn).Publish(ctx)
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	prefix := &pb.Path{Target: "COUNTERS_DB"}
+	path1 := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: "Ethernet68"}}}
+	path2 := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: "Ethernet72"}}}
+	notification := func(ts int64, paths ...*pb.Path) *pb.SubscribeResponse {
+		n := &pb.Notification{Timestamp: ts, Prefix: prefix}
+		for _, p := range paths {
+			n.Update = append(n.Update, &pb.Update{Path: p, Val: &pb.TypedValue{
+				Value: &pb.TypedValue_StringVal{StringVal: "up"}}})
+		}
+		return &pb.SubscribeResponse{Response: &pb.SubscribeResponse_Update{Update: n}}
+	}
+
+	dest, other := "127.0.0.1:8082", "127.0.0.1:8083"
+	unacked := newUnackedStore()
+	unacked.add(dest, notification(1, path1, path2))
+	unacked.add(dest, notification(2, path1))
+	unacked.add(other, notification(1, path1, path2))
+
+	// Partial acknowledgement keeps the remaining paths of the notification
+	if n := unacked.ack(dest, &spb.PublishResponse{Timestamp: 1, Prefix: prefix, Path: []*pb.Path{path1}}); n != 0 {
+		t.Fatalf("Partial ack removed %v notifications", n)
+	}
+	pending := unacked.pending(dest)
+	if len(pending) != 2 || len(pending[0].GetUpdate().GetUpdate()) != 1 ||
+		!reflect.DeepEqual(pending[0].GetUpdate().GetUpdate()[0].GetPath(), path2) {
+		t.Fatalf("Unexpected pending notifications after partial ack: %v", pending)
+	}
+
+	// The server acknowledges all paths of each notification received
+	for _, resp := range pending {
+		if err = pub.Send(resp); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		pr, err := pub.Recv()
+		if err != nil {
+			t.Fatalf("Recv of PublishResponse failed: %v", err)
+		}
+		if pr.GetTimestamp() != resp.GetUpdate().GetTimestamp() || len(pr.GetPath()) != len(resp.GetUpdate().GetUpdate()) {
+			t.Fatalf("Unexpected PublishResponse %v for %v", pr, resp)
+		}
+		if n := unacked.ack(dest, pr); n != 1 {
+			t.Fatalf("Ack %v removed %v notifications, want 1", pr, n)
+		}
+	}
+	if pending = unacked.pending(dest); len(pending) != 0 {
+		t.Fatalf("Unexpected pending notifications after ack: %v", pending)
+	}
+
+	// The acknowledgements of one destination leave the other one's pending
+	if pending = unacked.pending(other); len(pending) != 1 || len(pending[0].GetUpdate().GetUpdate()) != 2 {
+		t.Fatalf("Unexpected pending notifications of the other destination: %v", pending)
+	}
+	unacked.keep([]string{dest})
+	if pending = unacked.pending(other); len(pending) != 0 {
+		t.Fatalf("Unexpected pending notifications of a destination no longer in use: %v", pending)
+	}
+}
+
+func TestGNMIDialOutFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dialout_store")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Tiny files so that every record rotates the file, keeping the last 3
+	store, err := sds.NewFileStore(sds.FileStoreConfig{Dir: dir, MaxFileSize: 1, MaxFiles: 3})
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	defer store.Close()
+	s := createServer(t, &sds.Config{Port: 8083})
+	s.SetDataStore(store)
+	go runServer(t, s)
+	defer s.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, "127.0.0.1:8083", grpc.WithBlock(),
+		grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
+	if err != nil {
+		t.Fatalf("Dial to dialout server failed: %v", err)
+	}
+	defer conn.Close()
+	pub, err := spb.NewGNMIDialOutClient(conn).Publish(ctx)
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tables := []string{"COUNTERS", "COUNTERS", "COUNTERS_PORT_NAME_MAP", "COUNTERS"}
+	for i, table := range tables {
+		resp := &pb.SubscribeResponse{Response: &pb.SubscribeResponse_Update{Update: &pb.Notification{
+			Timestamp: base.Add(time.Duration(i) * time.Minute).UnixNano(),
+			Prefix:    &pb.Path{Target: "COUNTERS_DB"},
+			Update: []*pb.Update{{
+				Path: &pb.Path{Elem: []*pb.PathElem{{Name: table}, {Name: "Ethernet68"}}},
+				Val:  &pb.TypedValue{Value: &pb.TypedValue_StringVal{StringVal: "up"}},
+			}},
+		}}}
+		if err = pub.Send(resp); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	pub.CloseSend()
+
+	query := func(q *sds.Query) []*sds.Record {
+		var recs []*sds.Record
+		if err := store.Query(q, func(rec *sds.Record) error {
+			recs = append(recs, rec)
+			return nil
+		}); err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		return recs
+	}
+	// The oldest file was removed by retention
+	for i := 0; i < 50 && len(query(nil)) < len(tables)-1; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	tests := []struct {
+		desc  string
+		query *sds.Query
+		want  []int64 // minutes after base of the matching records
+	}{{
+		desc:  "all",
+		query: &sds.Query{},
+		want:  []int64{1, 2, 3},
+	}, {
+		desc:  "path",
+		query: &sds.Query{Path: "/COUNTERS_DB/COUNTERS"},
+		want:  []int64{1, 3},
+	}, {
+		desc:  "time range",
+		query: &sds.Query{Start: base.Add(2 * time.Minute), End: base.Add(3 * time.Minute)},
+		want:  []int64{2},
+	}, {
+		desc:  "device",
+		query: &sds.Query{Device: "127.0.0.1", Path: "/COUNTERS_DB/COUNTERS_PORT_NAME_MAP/Ethernet68"},
+		want:  []int64{2},
+	}, {
+		desc:  "other device",
+		query: &sds.Query{Device: "10.0.0.1"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []int64
+			for _, rec := range query(tt.query) {
+				got = append(got, int64(rec.Time().Sub(base)/time.Minute))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got records %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeDialOutUpdates(t *testing.T) {
+	path := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: "Ethernet68"}}}
+	jv := []byte(`{"SAI_PORT_STAT_PFC_7_RX_PKTS":"2","admin":true,"mtu":9100,"speed":1.5,"lanes":["1","2"]}`)
+	updates := []*pb.Update{{Path: path, Val: &pb.TypedValue{
+		Value: &pb.TypedValue_JsonIetfVal{JsonIetfVal: jv}}}}
+	leaf := func(name string) *pb.Path {
+		return &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: "Ethernet68"}, {Name: name}}}
+	}
+
+	tests := []struct {
+		desc     string
+		encoding pb.Encoding
+		want     []*pb.Update
+	}{{
+		desc:     "JSON_IETF is unchanged",
+		encoding: pb.Encoding_JSON_IETF,
+		want:     updates,
+	}, {
+		desc:     "JSON",
+		encoding: pb.Encoding_JSON,
+		want:     []*pb.Update{{Path: path, Val: &pb.TypedValue{Value: &pb.TypedValue_JsonVal{JsonVal: jv}}}},
+	}, {
+		desc:     "BYTES",
+		encoding: pb.Encoding_BYTES,
+		want:     []*pb.Update{{Path: path, Val: &pb.TypedValue{Value: &pb.TypedValue_BytesVal{BytesVal: jv}}}},
+	}, {
+		desc:     "ASCII",
+		encoding: pb.Encoding_ASCII,
+		want:     []*pb.Update{{Path: path, Val: &pb.TypedValue{Value: &pb.TypedValue_AsciiVal{AsciiVal: string(jv)}}}},
+	}, {
+		desc:     "PROTO splits JSON into scalar leaves",
+		encoding: pb.Encoding_PROTO,
+		want: []*pb.Update{
+			{Path: leaf("SAI_PORT_STAT_PFC_7_RX_PKTS"), Val: &pb.TypedValue{Value: &pb.TypedValue_StringVal{StringVal: "2"}}},
+			{Path: leaf("admin"), Val: &pb.TypedValue{Value: &pb.TypedValue_BoolVal{BoolVal: true}}},
+			{Path: leaf("lanes"), Val: &pb.TypedValue{Value: &pb.TypedValue_LeaflistVal{LeaflistVal: &pb.ScalarArray{
+				Element: []*pb.TypedValue{
+					{Value: &pb.TypedValue_StringVal{StringVal: "1"}},
+					{Value: &pb.TypedValue_StringVal{StringVal: "2"}},
+				}}}}},
+			{Path: leaf("mtu"), Val: &pb.TypedValue{Value: &pb.TypedValue_IntVal{IntVal: 9100}}},
+			{Path: leaf("speed"), Val: &pb.TypedValue{Value: &pb.TypedValue_DecimalVal{DecimalVal: &pb.Decimal64{Digits: 15, Precision: 1}}}},
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := encodeUpdates(updates, tt.encoding)
+			if err != nil {
+				t.Fatalf("encodeUpdates failed: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v updates, want %v: %v", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("update %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := ParseEncoding("xml"); err == nil {
+		t.Errorf("ParseEncoding accepted an unsupported encoding")
+	}
+	if enc, err := ParseEncoding("proto"); err != nil || enc != pb.Encoding_PROTO {
+		t.Errorf("ParseEncoding(proto) = %v, %v", enc, err)
+	}
+}
+
+func writeTestCertFiles(t *testing.T, dir string) (string, string) {
+	cert, err := testcert.NewCert()
+	if err != nil {
+		t.Fatalf("could not create key pair: %v", err)
+	}
+	crtFile := dir + "/client.crt"
+	keyFile := dir + "/client.key"
+	crt := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	key := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(cert.PrivateKey.(*rsa.PrivateKey))})
+	if err = ioutil.WriteFile(crtFile, crt, 0644); err != nil {
+		t.Fatalf("write %v failed: %v", crtFile, err)
+	}
+	if err = ioutil.WriteFile(keyFile, key, 0600); err != nil {
+		t.Fatalf("write %v failed: %v", keyFile, err)
+	}
+	return crtFile, keyFile
+}
+
+func TestJSONToDecimal(t *testing.T) {
+	tests := []struct {
+		num       string
+		digits    int64
+		precision uint32
+	}{
+		{"123456.789", 123456789, 3},
+		{"-2.50", -25, 1},
+		{"1e-3", 1, 3},
+		{"1.5e2", 150, 0},
+		{"0.1234567890123456789", 123456789012345678, 18},
+	}
+	for _, tt := range tests {
+		d, err := jsonToDecimal(json.Number(tt.num))
+		if err != nil {
+			t.Errorf("jsonToDecimal(%v) failed: %v", tt.num, err)
+			continue
+		}
+		if d.GetDigits() != tt.digits || d.GetPrecision() != tt.precision {
+			t.Errorf("jsonToDecimal(%v) = %v, want digits %v precision %v", tt.num, d, tt.digits, tt.precision)
+		}
+	}
+	if _, err := jsonToDecimal(json.Number("1e300")); err == nil {
+		t.Errorf("jsonToDecimal accepted a number out of range")
+	}
+}
+
+func TestDestinationTLSReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dialout_tls")
+	if err != nil {
+		t.Fatalf("TempDir failed: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	crtFile, keyFile := writeTestCertFiles(t, dir)
+
+	dt := &DestinationTLS{
+		CaCrt:      crtFile,
+		ClientCrt:  crtFile,
+		ClientKey:  keyFile,
+		ServerName: "example.com",
+	}
+	if changed, err := dt.refresh(); err != nil || !changed {
+		t.Fatalf("first refresh = %v, %v, want true, nil", changed, err)
+	}
+	cfg, err := dt.config()
+	if err != nil {
+		t.Fatalf("config failed: %v", err)
+	}
+	if cfg.ServerName != "example.com" || cfg.RootCAs == nil || len(cfg.Certificates) != 1 || cfg.InsecureSkipVerify {
+		t.Fatalf("unexpected TLS config %+v", cfg)
+	}
+	if changed, err := dt.refresh(); err != nil || changed {
+		t.Fatalf("refresh of unchanged files = %v, %v, want false, nil", changed, err)
+	}
+
+	// Replace the certificate files, the config must be reloaded
+	oldCert := cfg.Certificates[0].Certificate[0]
+	writeTestCertFiles(t, dir)
+	later := time.Now().Add(time.Minute)
+	for _, f := range []string{crtFile, keyFile} {
+		if err = os.Chtimes(f, later, later); err != nil {
+			t.Fatalf("Chtimes failed: %v", err)
+		}
+	}
+	if changed, err := dt.refresh(); err != nil || !changed {
+		t.Fatalf("refresh of modified files = %v, %v, want true, nil", changed, err)
+	}
+	if cfg, _ = dt.config(); reflect.DeepEqual(cfg.Certificates[0].Certificate[0], oldCert) {
+		t.Fatalf("client certificate was not reloaded")
+	}
+
+	// A reload by a connection attempt still reconnects the group
+	destGrpNameMap["tls_group"] = []Destination{{Addrs: "127.0.0.1:8080", TLS: dt}}
+	defer delete(destGrpNameMap, "tls_group")
+	dt.appliedGen = dt.generation()
+	later = later.Add(time.Minute)
+	os.Chtimes(crtFile, later, later)
+	if _, err = dt.config(); err != nil {
+		t.Fatalf("config failed: %v", err)
+	}
+	refreshDestGroupTLS(context.Background())
+	if dt.appliedGen != dt.generation() {
+		t.Fatalf("TLS config reloaded by a connection attempt was not applied to the group")
+	}
+
+	// A broken file keeps the current config
+	if err = ioutil.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("write %v failed: %v", keyFile, err)
+	}
+	os.Chtimes(keyFile, later.Add(time.Minute), later.Add(time.Minute))
+	if _, err := dt.refresh(); err == nil {
+		t.Fatalf("refresh accepted an invalid key file")
+	}
+	if dt.cfg != cfg {
+		t.Fatalf("TLS config replaced by an invalid one")
+	}
+}
+
+// fakePublishStream records the responses sent on a Publish stream, or
+// fails to send them if broken.
+type fakePublishStream struct {
+	grpc.ClientStream
+	sent   []*pb.SubscribeResponse
+	broken bool
+}
+
+func (f *fakePublishStream) Send(resp *pb.SubscribeResponse) error {
+	if f.broken {
+		return io.ErrClosedPipe
+	}
+	f.sent = append(f.sent, resp)
+	return nil
+}
+
+func (f *fakePublishStream) Recv() (*spb.PublishResponse, error) {
+	return nil, io.EOF
+}
+
+func TestPublishDstModes(t *testing.T) {
+	var paths []*pb.Path
+	n := &pb.Notification{Timestamp: 1, Prefix: &pb.Path{Target: "COUNTERS_DB"}}
+	for _, port := range []string{"Ethernet0", "Ethernet4", "Ethernet8", "Ethernet12", "Ethernet16", "Ethernet20"} {
+		p := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: port}}}
+		paths = append(paths, p)
+		n.Update = append(n.Update, &pb.Update{Path: p, Val: &pb.TypedValue{
+			Value: &pb.TypedValue_StringVal{StringVal: port}}})
+	}
+	resp := &pb.SubscribeResponse{Response: &pb.SubscribeResponse_Update{Update: n}}
+	syncResp := &pb.SubscribeResponse{Response: &pb.SubscribeResponse_SyncResponse{SyncResponse: true}}
+
+	for _, mode := range []string{"failover", "broadcast", "hash"} {
+		t.Run(mode, func(t *testing.T) {
+			m, err := NewDstMode(mode)
+			if err != nil {
+				t.Fatalf("NewDstMode(%v) failed: %v", mode, err)
+			}
+			cs := &clientSubscription{name: "test", dstMode: m}
+			streams := []*fakePublishStream{{}, {}, {}}
+			var conns []*destConn
+			for _, f := range streams {
+				conns = append(conns, &destConn{client: &Client{publish: f}})
+			}
+			if m == Failover {
+				conns = conns[:1]
+				streams = streams[:1]
+			}
+			for _, r := range []*pb.SubscribeResponse{resp, syncResp} {
+				if err = cs.publish(context.Background(), conns, r); err != nil {
+					t.Fatalf("publish failed: %v", err)
+				}
+			}
+
+			got := map[string]int{}
+			for i, f := range streams {
+				if len(f.sent) == 0 || !f.sent[len(f.sent)-1].GetSyncResponse() {
+					t.Fatalf("destination %d did not receive the sync response: %v", i, f.sent)
+				}
+				for _, r := range f.sent {
+					for _, u := range r.GetUpdate().GetUpdate() {
+						got[u.GetPath().String()]++
+						if m == Hash && pathShard(n.GetPrefix(), u.GetPath(), len(conns)) != i {
+							t.Errorf("update %v sent to destination %d", u.GetPath(), i)
+						}
+					}
+				}
+			}
+			want := 1
+			if m == Broadcast {
+				want = len(streams)
+			}
+			for _, p := range paths {
+				if got[p.String()] != want {
+					t.Errorf("update %v sent %d times, want %d", p, got[p.String()], want)
+				}
+			}
+		})
+	}
+
+	if _, err := NewDstMode("random"); err == nil {
+		t.Errorf("NewDstMode accepted an unknown mode")
+	}
+}
+
+func TestPublishDstFailure(t *testing.T) {
+	n := &pb.Notification{Timestamp: 1, Prefix: &pb.Path{Target: "COUNTERS_DB"}}
+	for _, port := range []string{"Ethernet0", "Ethernet4", "Ethernet8", "Ethernet12", "Ethernet16", "Ethernet20"} {
+		n.Update = append(n.Update, &pb.Update{
+			Path: &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: port}}},
+			Val:  &pb.TypedValue{Value: &pb.TypedValue_StringVal{StringVal: port}}})
+	}
+	resp := &pb.SubscribeResponse{Response: &pb.SubscribeResponse_Update{Update: n}}
+
+	for _, m := range []dstMode{Failover, Broadcast, Hash} {
+		t.Run(m.String(), func(t *testing.T) {
+			cs := &clientSubscription{name: "test", dstMode: m}
+			streams := []*fakePublishStream{{}, {broken: true}, {}}
+			var conns []*destConn
+			for _, f := range streams {
+				conns = append(conns, &destConn{client: &Client{publish: f}})
+			}
+			if m == Failover {
+				conns = conns[1:2]
+				streams = streams[1:2]
+			}
+
+			err := cs.publish(context.Background(), conns, resp)
+			if m == Failover {
+				if err == nil {
+					t.Fatalf("publish to a broken destination succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("publish failed: %v", err)
+			}
+			if conns[1].client != nil {
+				t.Errorf("broken destination was not dropped")
+			}
+			got := map[string]int{}
+			for i, f := range streams {
+				if i != 1 && len(f.sent) == 0 {
+					t.Errorf("destination %d received nothing", i)
+				}
+				for _, r := range f.sent {
+					for _, u := range r.GetUpdate().GetUpdate() {
+						got[u.GetPath().String()]++
+					}
+				}
+			}
+			// The share of the broken destination is re-hashed over the others
+			want := 1
+			if m == Broadcast {
+				want = 2
+			}
+			for _, u := range n.GetUpdate() {
+				if got[u.GetPath().String()] != want {
+					t.Errorf("update %v sent %d times, want %d", u.GetPath(), got[u.GetPath().String()], want)
+				}
+			}
+
+			// Once no destination is left, publish fails
+			for _, f := range streams {
+				f.broken = true
+			}
+			if err = cs.publish(context.Background(), conns, resp); err == nil {
+				t.Errorf("publish without a connected destination succeeded")
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	defer func(cfg *ClientConfig) { clientCfg = cfg }(clientCfg)
+	clientCfg = &ClientConfig{RetryInterval: 5 * time.Second}
+	for failures := uint(0); failures < 40; failures++ {
+		d := retryBackoff(failures)
+		if d <= 0 || d > clientCfg.RetryInterval {
+			t.Fatalf("retryBackoff(%d) = %v out of range", failures, d)
+		}
+		// Lower bound of the jittered delay doubles until the retry interval
+		bound := minRetryBackoff << failures / 2
+		if failures >= 6 {
+			bound = clientCfg.RetryInterval / 2
+		}
+		if d < bound {
+			t.Fatalf("retryBackoff(%d) = %v, want at least %v", failures, d, bound)
+		}
+	}
+}
+
 func init() {
 	// Inform gNMI server to use redis tcp localhost connection
 	sdc.UseRedisLocalTcpPort = true
@@ -15,23 +15,29 @@ import (
 
 var (
 	clientCfg = dc.ClientConfig{
-		SrcIp:          "",
-		RetryInterval:  30 * time.Second,
-		Encoding:       gpb.Encoding_JSON_IETF,
-		Unidirectional: true,
-		TLS:            &tls.Config{},
+		SrcIp:         "",
+		RetryInterval: 30 * time.Second,
+		Encoding:      gpb.Encoding_JSON_IETF,
+		TLS:           &tls.Config{},
 	}
+	encoding = flag.String("encoding", "JSON_IETF", "Encoding of the published values: JSON_IETF, JSON, PROTO, ASCII or BYTES")
 )
 
 func init() {
 	flag.StringVar(&clientCfg.TLS.ServerName, "server_name", "", "When set, use this hostname to verify server certificate during TLS handshake.")
 	flag.BoolVar(&clientCfg.TLS.InsecureSkipVerify, "insecure", false, "When set, client will not verify the server certificate during TLS handshake.")
 	flag.DurationVar(&clientCfg.RetryInterval, "retry_interval", 30*time.Second, "Interval at which client tries to reconnect to destination servers")
-	flag.BoolVar(&clientCfg.Unidirectional, "unidirectional", true, "No repesponse from server is expected")
+	flag.BoolVar(&clientCfg.Unidirectional, "unidirectional", true, "No response from server is expected. When false, notifications are retained until the server acknowledges them and retransmitted after a reconnect")
 }
 
 func main() {
 	flag.Parse()
+	enc, err := dc.ParseEncoding(*encoding)
+	if err != nil {
+		log.Errorf("Invalid encoding: %v", err)
+		return
+	}
+	clientCfg.Encoding = enc
 	ctx, cancel := context.WithCancel(context.Background())
 	// Terminate on Ctrl+C
 	go func() {
@@ -41,7 +47,7 @@ func main() {
 		cancel()
 	}()
 	log.V(1).Infof("Starting telemetry publish client")
-	err := dc.DialOutRun(ctx, &clientCfg)
+	err = dc.DialOutRun(ctx, &clientCfg)
 	log.V(1).Infof("Exiting telemetry publish client: %v", err)
 	log.Flush()
 }
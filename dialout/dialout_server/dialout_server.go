@@ -15,6 +15,7 @@ import (
 	"net"
 	"strings"
 	"sync"
+	"time"
 )
 
 var (
@@ -38,6 +39,9 @@ type Config struct {
 	// Port for the Server to listen on. If 0 or unset the Server will pick a port
 	// for this Server.
 	Port int64
+	// Acknowledge each received notification with a PublishResponse listing
+	// its paths, for publishers running in acknowledged mode.
+	Ack bool
 }
 
 // New returns an initialized Server.
@@ -97,7 +101,9 @@ func (srv *Server) Port() int64 {
 	return srv.config.Port
 }
 
-// Port returns the port the Server is listening to.
+// SetDataStore sets where the received SubscribeResponses are stored, either
+// an in-memory *[]*gpb.SubscribeResponse or a Store. They are printed to
+// stdout when no data store is set.
 func (srv *Server) SetDataStore(dataStore interface{}) {
 	srv.dataStore = dataStore
 }
@@ -168,6 +174,15 @@ func (c *Client) String() string {
 	return c.addr.String()
 }
 
+// device returns the address of the publishing device, without port.
+func (c *Client) device() string {
+	host, _, err := net.SplitHostPort(c.addr.String())
+	if err != nil {
+		return c.addr.String()
+	}
+	return host
+}
+
 // Run process streaming from publish client. The first message received must be a
 // SubscriptionList. Once the client is started, it will run until the stream
 // is closed or the schedule completes. For Poll queries the Run will block
@@ -202,6 +217,11 @@ func (c *Client) Run(srv *Server, stream spb.GNMIDialOut_PublishServer) (err err
 				log.V(1).Infof("unexpected type %T\n", srv.dataStore)
 			case *[]*gpb.SubscribeResponse:
 				*ds = append(*ds, subscribeResponse)
+			case Store:
+				rec := &Record{Device: c.device(), Received: time.Now(), Response: subscribeResponse}
+				if err := ds.Append(rec); err != nil {
+					log.V(1).Infof("Client %s store error: %v", c, err)
+				}
 			}
 		}
 		srv.sRWMu.Unlock()
@@ -211,11 +231,39 @@ func (c *Client) Run(srv *Server, stream spb.GNMIDialOut_PublishServer) (err err
 			utils.PrintProto(subscribeResponse)
 		}
 
-		// TODO: send back (m *PublishResponse))
+		if srv.config.Ack {
+			if err = c.ack(stream, subscribeResponse); err != nil {
+				return grpc.Errorf(grpc.Code(err), "failed to send PublishResponse: %v", err)
+			}
+		}
 	}
 	return grpc.Errorf(codes.InvalidArgument, "Exiting")
 }
 
+// ack sends back a PublishResponse for the paths of a received notification.
+// Sync responses are not acknowledged.
+func (c *Client) ack(stream spb.GNMIDialOut_PublishServer, resp *gpb.SubscribeResponse) error {
+	n := resp.GetUpdate()
+	if n == nil {
+		return nil
+	}
+	pr := &spb.PublishResponse{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    n.GetPrefix(),
+		Alias:     n.GetAlias(),
+	}
+	for _, u := range n.GetUpdate() {
+		pr.Path = append(pr.Path, u.GetPath())
+	}
+	pr.Path = append(pr.Path, n.GetDelete()...)
+	if err := stream.Send(pr); err != nil {
+		c.errors++
+		return err
+	}
+	c.sendMsg++
+	return nil
+}
+
 // Closing of client queue is triggered upon end of stream receive or stream error
 // or fatal error of any client go routine .
 // it will cause cancle of client context and exit of the send goroutines.
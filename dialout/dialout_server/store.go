@@ -0,0 +1,322 @@
+package dialout_server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Record is a SubscribeResponse received from a publishing device.
+type Record struct {
+	Device   string // Address of the publishing device
+	Received time.Time
+	Response *gpb.SubscribeResponse
+}
+
+// Time returns the notification timestamp of the record, or the time it was
+// received for responses without timestamp.
+func (r *Record) Time() time.Time {
+	if ts := r.Response.GetUpdate().GetTimestamp(); ts != 0 {
+		return time.Unix(0, ts)
+	}
+	return r.Received
+}
+
+// Paths returns the full paths of the updates and deletes of the record, as
+// strings starting with the prefix target.
+func (r *Record) Paths() []string {
+	n := r.Response.GetUpdate()
+	if n == nil {
+		return nil
+	}
+	var paths []string
+	add := func(p *gpb.Path) {
+		elems := append(append([]*gpb.PathElem{}, n.GetPrefix().GetElem()...), p.GetElem()...)
+		s, err := ygot.PathToString(&gpb.Path{Elem: elems})
+		if err != nil {
+			s = p.String()
+		}
+		if target := n.GetPrefix().GetTarget(); target != "" {
+			s = "/" + target + s
+		}
+		paths = append(paths, s)
+	}
+	for _, u := range n.GetUpdate() {
+		add(u.GetPath())
+	}
+	for _, d := range n.GetDelete() {
+		add(d)
+	}
+	return paths
+}
+
+// Store persists the SubscribeResponses received by the Server.
+type Store interface {
+	Append(rec *Record) error
+	Close() error
+}
+
+// Query selects records of a Store. Empty fields match all records.
+type Query struct {
+	Device string
+	// Path prefix, starting with the target, e.g. "/COUNTERS_DB/COUNTERS"
+	Path  string
+	Start time.Time // inclusive
+	End   time.Time // exclusive
+}
+
+// Match reports whether rec is selected by the query. Sync responses have no
+// path and only match queries without Path.
+func (q *Query) Match(rec *Record) bool {
+	if q.Device != "" && q.Device != rec.Device {
+		return false
+	}
+	t := rec.Time()
+	if !q.Start.IsZero() && t.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && !t.Before(q.End) {
+		return false
+	}
+	if q.Path == "" {
+		return true
+	}
+	prefix := strings.TrimSuffix(q.Path, "/")
+	for _, p := range rec.Paths() {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// FileStoreConfig configures the rotation and retention of a FileStore.
+// Zero values disable the corresponding limit.
+type FileStoreConfig struct {
+	// Directory of the store files
+	Dir string
+	// Rotate the current file once it reaches this size in bytes
+	MaxFileSize int64
+	// Rotate the current file once it is open for this long
+	RotateInterval time.Duration
+	// Remove files whose last record is older than this
+	MaxAge time.Duration
+	// Keep at most this number of files
+	MaxFiles int
+}
+
+// FileStore is a Store writing the records as JSON lines into rotating files
+// of a directory.
+type FileStore struct {
+	config  FileStoreConfig
+	mu      sync.Mutex
+	file    *os.File
+	w       *bufio.Writer
+	size    int64
+	created time.Time
+}
+
+const (
+	storeFilePrefix = "dialout-"
+	storeFileSuffix = ".jsonl"
+)
+
+// fileRecord is the JSON line of a Record.
+type fileRecord struct {
+	Device   string          `json:"device"`
+	Received time.Time       `json:"received"`
+	Response json.RawMessage `json:"response"`
+}
+
+// NewFileStore returns a FileStore writing into config.Dir, creating the
+// directory if needed.
+func NewFileStore(config FileStoreConfig) (*FileStore, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("store directory not provided")
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("create store directory %v: %v", config.Dir, err)
+	}
+	return &FileStore{config: config}, nil
+}
+
+// Append writes rec to the current file, rotating it first if needed.
+func (fs *FileStore) Append(rec *Record) error {
+	resp, err := protojson.Marshal(rec.Response)
+	if err != nil {
+		return fmt.Errorf("marshal %v: %v", rec.Response, err)
+	}
+	line, err := json.Marshal(&fileRecord{Device: rec.Device, Received: rec.Received, Response: resp})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.file != nil && fs.needRotate(rec.Received) {
+		if err = fs.closeFile(); err != nil {
+			return err
+		}
+	}
+	if fs.file == nil {
+		if err = fs.openFile(rec.Received); err != nil {
+			return err
+		}
+	}
+	n, err := fs.w.Write(line)
+	fs.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return fs.w.Flush()
+}
+
+func (fs *FileStore) needRotate(now time.Time) bool {
+	if fs.config.MaxFileSize > 0 && fs.size >= fs.config.MaxFileSize {
+		return true
+	}
+	return fs.config.RotateInterval > 0 && now.Sub(fs.created) >= fs.config.RotateInterval
+}
+
+func (fs *FileStore) openFile(now time.Time) error {
+	// Zero padded nanoseconds keep the file names in time order
+	name := filepath.Join(fs.config.Dir, fmt.Sprintf("%s%020d%s", storeFilePrefix, now.UnixNano(), storeFileSuffix))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("open store file %v: %v", name, err)
+	}
+	fs.file = f
+	fs.w = bufio.NewWriter(f)
+	fs.size = 0
+	fs.created = now
+	log.V(2).Infof("Opened store file %v", name)
+	fs.applyRetention(now)
+	return nil
+}
+
+func (fs *FileStore) closeFile() error {
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.w.Flush()
+	if cerr := fs.file.Close(); err == nil {
+		err = cerr
+	}
+	fs.file = nil
+	fs.w = nil
+	return err
+}
+
+// applyRetention removes the files exceeding MaxFiles or older than MaxAge.
+// The current file is never removed.
+func (fs *FileStore) applyRetention(now time.Time) {
+	files, err := storeFiles(fs.config.Dir)
+	if err != nil {
+		log.V(1).Infof("List store files failed: %v", err)
+		return
+	}
+	current := ""
+	if fs.file != nil {
+		current = fs.file.Name()
+	}
+	for i, f := range files {
+		if f == current {
+			continue
+		}
+		remove := fs.config.MaxFiles > 0 && len(files)-i > fs.config.MaxFiles
+		if !remove && fs.config.MaxAge > 0 {
+			if fi, err := os.Stat(f); err == nil && now.Sub(fi.ModTime()) > fs.config.MaxAge {
+				remove = true
+			}
+		}
+		if remove {
+			if err := os.Remove(f); err != nil {
+				log.V(1).Infof("Remove store file %v failed: %v", f, err)
+				continue
+			}
+			log.V(2).Infof("Removed store file %v", f)
+		}
+	}
+}
+
+// Close flushes and closes the current file.
+func (fs *FileStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.closeFile()
+}
+
+// Query calls fn for the records matching q, in the order they were stored.
+func (fs *FileStore) Query(q *Query, fn func(rec *Record) error) error {
+	fs.mu.Lock()
+	if fs.w != nil {
+		fs.w.Flush()
+	}
+	fs.mu.Unlock()
+	return QueryFileStore(fs.config.Dir, q, fn)
+}
+
+// QueryFileStore calls fn for the records of the FileStore directory dir
+// matching q, in the order they were stored.
+func QueryFileStore(dir string, q *Query, fn func(rec *Record) error) error {
+	files, err := storeFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err = queryFile(name, q, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func queryFile(name string, q *Query, fn func(rec *Record) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var fr fileRecord
+		if err = json.Unmarshal(scanner.Bytes(), &fr); err != nil {
+			return fmt.Errorf("%v:%d: %v", name, line, err)
+		}
+		rec := &Record{Device: fr.Device, Received: fr.Received, Response: &gpb.SubscribeResponse{}}
+		if err = protojson.Unmarshal(fr.Response, rec.Response); err != nil {
+			return fmt.Errorf("%v:%d: %v", name, line, err)
+		}
+		if q != nil && !q.Match(rec) {
+			continue
+		}
+		if err = fn(rec); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// storeFiles returns the store files of dir, oldest first.
+func storeFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, storeFilePrefix+"*"+storeFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -4,11 +4,19 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"flag"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"os/signal"
+	"time"
 
 	log "github.com/golang/glog"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	spb "github.com/sonic-net/sonic-gnmi/proto"
 
 	ds "github.com/sonic-net/sonic-gnmi/dialout/dialout_server"
 	testcert "github.com/sonic-net/sonic-gnmi/testdata/tls"
@@ -22,11 +30,115 @@ var (
 	serverKey         = flag.String("server_key", "", "TLS server private key")
 	insecure          = flag.Bool("insecure", false, "Skip providing TLS cert and key, for testing only!")
 	allowNoClientCert = flag.Bool("allow_no_client_auth", false, "When set, telemetry server will request but not require a client certificate.")
+	ack               = flag.Bool("ack", false, "Acknowledge received notifications with PublishResponse, for publishers not in unidirectional mode.")
+	// Persistent store of the received notifications.
+	storeDir            = flag.String("store_dir", "", "Directory to store the received notifications in. They are printed to stdout if not set.")
+	storeMaxFileSize    = flag.Int64("store_max_file_size", 64*1024*1024, "Rotate store files once they reach this size in bytes, 0 for no limit.")
+	storeRotateInterval = flag.Duration("store_rotate_interval", time.Hour, "Rotate store files after this duration, 0 for no limit.")
+	storeMaxAge         = flag.Duration("store_max_age", 0, "Remove store files older than this duration, 0 to keep them.")
+	storeMaxFiles       = flag.Int("store_max_files", 0, "Keep at most this number of store files, 0 for no limit.")
+	// Query and replay of the store, instead of running the server.
+	query      = flag.Bool("query", false, "Print the notifications of store_dir matching device, path, start and end as JSON lines, and exit.")
+	replayAddr = flag.String("replay_addr", "", "Publish the notifications of store_dir matching device, path, start and end to this collector address, and exit. The collector certificate is validated with ca_crt if set.")
+	device     = flag.String("device", "", "Only query or replay the notifications of this device address.")
+	path       = flag.String("path", "", "Only query or replay the notifications with a path under this prefix, starting with the target, e.g. /COUNTERS_DB/COUNTERS.")
+	start      = flag.String("start", "", "Only query or replay the notifications from this RFC3339 time.")
+	end        = flag.String("end", "", "Only query or replay the notifications before this RFC3339 time.")
 )
 
+// storeQuery returns the Query built from the query flags.
+func storeQuery() (*ds.Query, error) {
+	q := &ds.Query{Device: *device, Path: *path}
+	var err error
+	if *start != "" {
+		if q.Start, err = time.Parse(time.RFC3339, *start); err != nil {
+			return nil, fmt.Errorf("invalid start time %v: %v", *start, err)
+		}
+	}
+	if *end != "" {
+		if q.End, err = time.Parse(time.RFC3339, *end); err != nil {
+			return nil, fmt.Errorf("invalid end time %v: %v", *end, err)
+		}
+	}
+	return q, nil
+}
+
+// printStore prints the matching stored notifications as JSON lines.
+func printStore(q *ds.Query) error {
+	return ds.QueryFileStore(*storeDir, q, func(rec *ds.Record) error {
+		resp, err := protojson.Marshal(rec.Response)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("{\"device\":%q,\"received\":%q,\"response\":%s}\n",
+			rec.Device, rec.Received.Format(time.RFC3339Nano), resp)
+		return nil
+	})
+}
+
+// replayStore publishes the matching stored notifications to a collector.
+func replayStore(q *ds.Query) error {
+	tlsCfg := &tls.Config{InsecureSkipVerify: true}
+	if *caCert != "" {
+		ca, err := ioutil.ReadFile(*caCert)
+		if err != nil {
+			return fmt.Errorf("could not read CA certificate: %v", err)
+		}
+		certPool := x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return fmt.Errorf("failed to append CA certificate")
+		}
+		tlsCfg = &tls.Config{RootCAs: certPool}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt)
+		<-c
+		cancel()
+	}()
+
+	conn, err := grpc.DialContext(ctx, *replayAddr, grpc.WithBlock(), grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	if err != nil {
+		return fmt.Errorf("dial to %v: %v", *replayAddr, err)
+	}
+	defer conn.Close()
+	pub, err := spb.NewGNMIDialOutClient(conn).Publish(ctx)
+	if err != nil {
+		return fmt.Errorf("publish to %v: %v", *replayAddr, err)
+	}
+	count := 0
+	err = ds.QueryFileStore(*storeDir, q, func(rec *ds.Record) error {
+		count++
+		return pub.Send(rec.Response)
+	})
+	pub.CloseSend()
+	log.V(1).Infof("Replayed %d notifications to %v", count, *replayAddr)
+	return err
+}
+
 func main() {
 	flag.Parse()
 
+	if *query || *replayAddr != "" {
+		if *storeDir == "" {
+			log.Errorf("store_dir must be set.")
+			return
+		}
+		q, err := storeQuery()
+		if err == nil && *query {
+			err = printStore(q)
+		} else if err == nil {
+			err = replayStore(q)
+		}
+		if err != nil {
+			log.Errorf("%v", err)
+		}
+		log.Flush()
+		return
+	}
+
 	switch {
 	case *port <= 0:
 		log.Errorf("port must be > 0.")
@@ -81,11 +193,27 @@ func main() {
 	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}
 	cfg := &ds.Config{}
 	cfg.Port = int64(*port)
+	cfg.Ack = *ack
 	s, err := ds.NewServer(cfg, opts)
 	if err != nil {
 		log.Errorf("Failed to create gNMI server: %v", err)
 		return
 	}
+	if *storeDir != "" {
+		store, err := ds.NewFileStore(ds.FileStoreConfig{
+			Dir:            *storeDir,
+			MaxFileSize:    *storeMaxFileSize,
+			RotateInterval: *storeRotateInterval,
+			MaxAge:         *storeMaxAge,
+			MaxFiles:       *storeMaxFiles,
+		})
+		if err != nil {
+			log.Errorf("Failed to create store: %v", err)
+			return
+		}
+		defer store.Close()
+		s.SetDataStore(store)
+	}
 
 	log.V(1).Infof("Starting RPC server on address: %s", s.Address())
 	s.Serve() // blocks until close
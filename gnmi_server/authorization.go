@@ -0,0 +1,457 @@
+package gnmi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-redis/redis"
+	log "github.com/golang/glog"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/sonic-net/sonic-gnmi/common_utils"
+	sdcfg "github.com/sonic-net/sonic-gnmi/sonic_db_config"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Full gRPC method names of the RPCs subject to authorization.
+const (
+	rpcGnmiGet                   = "/gnmi.gNMI/Get"
+	rpcGnmiSet                   = "/gnmi.gNMI/Set"
+	rpcGnmiSubscribe             = "/gnmi.gNMI/Subscribe"
+	rpcSystemKillProcess         = "/gnoi.system.System/KillProcess"
+	rpcSystemReboot              = "/gnoi.system.System/Reboot"
+	rpcSystemRebootStatus        = "/gnoi.system.System/RebootStatus"
+	rpcSystemCancelReboot        = "/gnoi.system.System/CancelReboot"
+	rpcSystemPing                = "/gnoi.system.System/Ping"
+	rpcSystemTraceroute          = "/gnoi.system.System/Traceroute"
+	rpcSystemSetPackage          = "/gnoi.system.System/SetPackage"
+	rpcSystemSwitchControlProc   = "/gnoi.system.System/SwitchControlProcessor"
+	rpcSystemTime                = "/gnoi.system.System/Time"
+	rpcFileGet                   = "/gnoi.file.File/Get"
+	rpcFilePut                   = "/gnoi.file.File/Put"
+	rpcFileStat                  = "/gnoi.file.File/Stat"
+	rpcFileRemove                = "/gnoi.file.File/Remove"
+	rpcFileTransferToRemote      = "/gnoi.file.File/TransferToRemote"
+	rpcOsInstall                 = "/gnoi.os.OS/Install"
+	rpcOsActivate                = "/gnoi.os.OS/Activate"
+	rpcOsVerify                  = "/gnoi.os.OS/Verify"
+	rpcJwtRefresh                = "/gnoi.sonic_jwt.SonicJwtService/Refresh"
+	rpcJwtRevoke                 = "/gnoi.sonic_jwt.SonicJwtService/Revoke"
+	rpcJwtListSessions           = "/gnoi.sonic_jwt.SonicJwtService/ListSessions"
+	rpcSonicClearNeighbors       = "/gnoi.sonic.SonicService/ClearNeighbors"
+	rpcSonicCopyConfig           = "/gnoi.sonic.SonicService/CopyConfig"
+	rpcSonicShowTechsupport      = "/gnoi.sonic.SonicService/ShowTechsupport"
+	rpcSonicImageInstall         = "/gnoi.sonic.SonicService/ImageInstall"
+	rpcSonicImageRemove          = "/gnoi.sonic.SonicService/ImageRemove"
+	rpcSonicImageDefault         = "/gnoi.sonic.SonicService/ImageDefault"
+	rpcDebugSubscribePreferences = "/gnoi.sonic.Debug/GetSubscribePreferences"
+)
+
+// AuthzRequest describes an operation to be authorized.
+type AuthzRequest struct {
+	// Full gRPC method name of the RPC
+	Rpc string
+	// Whether the operation modifies the device state
+	Write bool
+	// Origin of the accessed paths
+	Origin string
+	// Accessed paths, relative to Prefix
+	Prefix *gnmipb.Path
+	Paths  []*gnmipb.Path
+	// Accessed local files, as absolute paths
+	Files []string
+}
+
+// Authorizer decides whether an authenticated user may perform an operation.
+// Authorize returns a PermissionDenied error when the operation is not allowed.
+type Authorizer interface {
+	Authorize(auth *common_utils.AuthInfo, req *AuthzRequest) error
+}
+
+// RolePolicy lists what the users of a role are allowed to do. An empty
+// Origins list allows all origins. Paths are gNMI path string prefixes
+// starting with the target, or the first element when there is no target;
+// "*" matches any single element and "/" matches all paths. An element with
+// keys, like PORT[name=Ethernet0], only matches the elements with the same
+// key values, "*" matching any value; an element without keys matches any
+// keys. RPCs without paths, like most gNOI operations, need a non-empty Read
+// list, or Write list for write RPCs. Local files are allowed by the FileRead
+// and FileWrite directories, and their subdirectories.
+type RolePolicy struct {
+	Rpcs      []string `json:"rpcs"`
+	Origins   []string `json:"origins"`
+	Read      []string `json:"read"`
+	Write     []string `json:"write"`
+	FileRead  []string `json:"file_read"`
+	FileWrite []string `json:"file_write"`
+}
+
+// AuthzPolicy maps role names to their RolePolicy.
+type AuthzPolicy struct {
+	Roles map[string]*RolePolicy `json:"roles"`
+}
+
+// Interval between the reloads of policies read from CONFIG_DB.
+var authzTableReloadInterval = 30 * time.Second
+
+// PolicyAuthorizer is an Authorizer enforcing an AuthzPolicy. The policy is
+// reloaded while the server is running, when its source changes, see Watch.
+type PolicyAuthorizer struct {
+	mu     sync.RWMutex
+	policy *AuthzPolicy
+	// Reload function of the policy source
+	load func() (*AuthzPolicy, error)
+	// Policy file, empty if the policy is read from CONFIG_DB
+	fileName string
+}
+
+// NewFileAuthorizer returns a PolicyAuthorizer whose policy is read from a
+// JSON file.
+func NewFileAuthorizer(fileName string) (*PolicyAuthorizer, error) {
+	return newPolicyAuthorizer(fileName, func() (*AuthzPolicy, error) {
+		return loadAuthzPolicyFile(fileName)
+	})
+}
+
+// NewConfigDbAuthorizer returns a PolicyAuthorizer whose policy is read from
+// a CONFIG_DB table, keyed by role name, with comma separated "rpcs",
+// "origins", "read", "write", "file_read" and "file_write" fields.
+func NewConfigDbAuthorizer(tableName string) (*PolicyAuthorizer, error) {
+	return newPolicyAuthorizer("", func() (*AuthzPolicy, error) {
+		return loadAuthzPolicyConfigDb(tableName)
+	})
+}
+
+func newPolicyAuthorizer(fileName string, load func() (*AuthzPolicy, error)) (*PolicyAuthorizer, error) {
+	a := &PolicyAuthorizer{load: load, fileName: fileName}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Watch reloads the policy when its source changes, until stop is closed.
+// Policy files are reloaded when written or replaced, and CONFIG_DB tables
+// are read again every authzTableReloadInterval.
+func (a *PolicyAuthorizer) Watch(stop <-chan struct{}) error {
+	reload := func() {
+		if err := a.Reload(); err != nil {
+			log.Errorf("Keeping current authorization policy: %v", err)
+		}
+	}
+	if a.fileName == "" {
+		go func() {
+			ticker := time.NewTicker(authzTableReloadInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					reload()
+				case <-stop:
+					return
+				}
+			}
+		}()
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// The directory is watched, as editors and tools replace the file
+	if err = watcher.Add(filepath.Dir(a.fileName)); err != nil {
+		watcher.Close()
+		return err
+	}
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event := <-watcher.Events:
+				if filepath.Clean(event.Name) == filepath.Clean(a.fileName) &&
+					event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					log.V(1).Infof("Authorization policy %v changed", a.fileName)
+					reload()
+				}
+			case err := <-watcher.Errors:
+				log.Errorf("Error watching authorization policy %v: %v", a.fileName, err)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Reload reads the policy again from its source. The current policy is kept
+// if the new one cannot be loaded.
+func (a *PolicyAuthorizer) Reload() error {
+	policy, err := a.load()
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.policy = policy
+	a.mu.Unlock()
+	log.V(1).Infof("Authorization policy loaded for %d roles", len(policy.Roles))
+	return nil
+}
+
+func loadAuthzPolicyFile(fileName string) (*AuthzPolicy, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("read authorization policy %v err: %v", fileName, err)
+	}
+	policy := &AuthzPolicy{}
+	if err = json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("parse authorization policy %v err: %v", fileName, err)
+	}
+	if policy.Roles == nil {
+		policy.Roles = map[string]*RolePolicy{}
+	}
+	if err = policy.validate(); err != nil {
+		return nil, fmt.Errorf("authorization policy %v err: %v", fileName, err)
+	}
+	return policy, nil
+}
+
+// validate checks that the paths of the policy can be parsed.
+func (policy *AuthzPolicy) validate() error {
+	for role, rp := range policy.Roles {
+		if rp == nil {
+			return fmt.Errorf("role %v has no policy", role)
+		}
+		for _, prefix := range append(append([]string{}, rp.Read...), rp.Write...) {
+			if _, err := ygot.StringToStructuredPath(prefix); err != nil {
+				return fmt.Errorf("role %v has invalid path %q: %v", role, prefix, err)
+			}
+		}
+	}
+	return nil
+}
+
+// readConfigDbTable returns the entries of a CONFIG_DB table of the default
+// namespace, by key.
+func readConfigDbTable(tableName string) (map[string]map[string]string, error) {
+	ns, _ := sdcfg.GetDbDefaultNamespace()
+	addr, err := sdcfg.GetDbTcpAddr("CONFIG_DB", ns)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sdcfg.GetDbId("CONFIG_DB", ns)
+	if err != nil {
+		return nil, err
+	}
+	separator, err := sdcfg.GetDbSeparator("CONFIG_DB", ns)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(&redis.Options{
+		Network:     "tcp",
+		Addr:        addr,
+		Password:    "",
+		DB:          db,
+		DialTimeout: 0,
+	})
+	defer client.Close()
+
+	keys, err := client.Keys(tableName + separator + "*").Result()
+	if err != nil {
+		return nil, fmt.Errorf("read table %v err: %v", tableName, err)
+	}
+	entries := map[string]map[string]string{}
+	for _, key := range keys {
+		fv, err := client.HGetAll(key).Result()
+		if err != nil {
+			return nil, fmt.Errorf("read entry %v err: %v", key, err)
+		}
+		entries[strings.TrimPrefix(key, tableName+separator)] = fv
+	}
+	return entries, nil
+}
+
+func loadAuthzPolicyConfigDb(tableName string) (*AuthzPolicy, error) {
+	entries, err := readConfigDbTable(tableName)
+	if err != nil {
+		return nil, fmt.Errorf("read authorization table %v err: %v", tableName, err)
+	}
+	policy := &AuthzPolicy{Roles: map[string]*RolePolicy{}}
+	for role, fv := range entries {
+		policy.Roles[role] = &RolePolicy{
+			Rpcs:      splitPolicyList(fv["rpcs"]),
+			Origins:   splitPolicyList(fv["origins"]),
+			Read:      splitPolicyList(fv["read"]),
+			Write:     splitPolicyList(fv["write"]),
+			FileRead:  splitPolicyList(fv["file_read"]),
+			FileWrite: splitPolicyList(fv["file_write"]),
+		}
+	}
+	if err = policy.validate(); err != nil {
+		return nil, fmt.Errorf("authorization table %v err: %v", tableName, err)
+	}
+	return policy, nil
+}
+
+func splitPolicyList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Authorize allows the operation if any role of the user allows it.
+func (a *PolicyAuthorizer) Authorize(auth *common_utils.AuthInfo, req *AuthzRequest) error {
+	a.mu.RLock()
+	policy := a.policy
+	a.mu.RUnlock()
+
+	for _, role := range auth.Roles {
+		if rp, ok := policy.Roles[role]; ok && rp.allows(req) {
+			log.V(5).Infof("authorize user %v role %v for %v", auth.User, role, req.Rpc)
+			return nil
+		}
+	}
+	log.V(1).Infof("user %v with roles %v is not authorized for %v", auth.User, auth.Roles, req.Rpc)
+	return status.Errorf(codes.PermissionDenied, "user %v is not authorized for %v", auth.User, req.Rpc)
+}
+
+func (rp *RolePolicy) allows(req *AuthzRequest) bool {
+	if !matchPolicyRpc(rp.Rpcs, req.Rpc) {
+		return false
+	}
+	if len(rp.Origins) != 0 && !containsString(rp.Origins, req.Origin) {
+		return false
+	}
+	if len(req.Files) != 0 {
+		dirs := rp.FileRead
+		if req.Write {
+			dirs = rp.FileWrite
+		}
+		for _, f := range req.Files {
+			if !matchFileDirs(dirs, f) {
+				return false
+			}
+		}
+		return true
+	}
+	prefixes := rp.Read
+	if req.Write {
+		prefixes = rp.Write
+	}
+	// RPCs without paths need the access to some paths
+	if len(req.Paths) == 0 {
+		return len(prefixes) != 0
+	}
+	for _, p := range req.Paths {
+		if !matchPolicyPath(prefixes, req.Prefix, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPolicyRpc reports whether rpc is listed in rpcs, either exactly or by
+// a pattern ending with "*".
+func matchPolicyRpc(rpcs []string, rpc string) bool {
+	for _, r := range rpcs {
+		if r == rpc || (strings.HasSuffix(r, "*") && strings.HasPrefix(rpc, strings.TrimSuffix(r, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPolicyPath reports whether the path under prefix starts with one of
+// the policy prefixes.
+func matchPolicyPath(prefixes []string, prefix *gnmipb.Path, path *gnmipb.Path) bool {
+	var elems []*gnmipb.PathElem
+	target := prefix.GetTarget()
+	if target == "" {
+		target = path.GetTarget()
+	}
+	if target != "" {
+		elems = append(elems, &gnmipb.PathElem{Name: target})
+	}
+	elems = append(elems, prefix.GetElem()...)
+	elems = append(elems, path.GetElem()...)
+
+	for _, prefix := range prefixes {
+		p, err := ygot.StringToStructuredPath(prefix)
+		if err != nil || len(p.GetElem()) > len(elems) {
+			continue
+		}
+		matched := true
+		for i, pe := range p.GetElem() {
+			if !matchPolicyElem(pe, elems[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPolicyElem reports whether the path element e matches the policy
+// element pe, by name and by the keys of pe.
+func matchPolicyElem(pe *gnmipb.PathElem, e *gnmipb.PathElem) bool {
+	if pe.GetName() != "*" && pe.GetName() != e.GetName() {
+		return false
+	}
+	for k, v := range pe.GetKey() {
+		ev, ok := e.GetKey()[k]
+		if !ok || (v != "*" && v != ev) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFileDirs reports whether the absolute path is one of dirs or within
+// one of them.
+func matchFileDirs(dirs []string, path string) bool {
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		if path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks an authenticated request against the configured
+// Authorizer. All requests are allowed when no Authorizer is configured or
+// user authentication is disabled.
+func authorize(config *Config, ctx context.Context, req *AuthzRequest) error {
+	if config.Authorizer == nil {
+		return nil
+	}
+	rc, _ := common_utils.GetContext(ctx)
+	if !rc.Auth.AuthEnabled {
+		return nil
+	}
+	return config.Authorizer.Authorize(&rc.Auth, req)
+}
@@ -0,0 +1,221 @@
+package gnmi
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Default validity of OCSP responses without next update time.
+const ocspCacheDuration = time.Hour
+
+var errCertRevoked = fmt.Errorf("certificate revoked")
+
+// CertRevocationChecker rejects peer certificates revoked by a CRL file or by
+// the OCSP responders of their issuers. Its VerifyPeerCertificate method is
+// meant for tls.Config, after the chains have been verified.
+type CertRevocationChecker struct {
+	// CRL file in PEM or DER format, PEM files may hold several CRLs
+	CrlFile string
+	// Query the OCSP responders listed in the certificates
+	Ocsp bool
+	// Accept certificates whose OCSP status cannot be determined
+	OcspSoftFail bool
+	// Timeout of OCSP requests
+	OcspTimeout time.Duration
+
+	mu        sync.RWMutex
+	crls      []*x509.RevocationList
+	ocspCache map[string]*ocsp.Response
+}
+
+// NewCertRevocationChecker returns a checker loading the CRLs of crlFile, if
+// not empty, and querying OCSP responders if ocspEnabled.
+func NewCertRevocationChecker(crlFile string, ocspEnabled bool) (*CertRevocationChecker, error) {
+	c := &CertRevocationChecker{
+		CrlFile:     crlFile,
+		Ocsp:        ocspEnabled,
+		OcspTimeout: 5 * time.Second,
+		ocspCache:   map[string]*ocsp.Response{},
+	}
+	if crlFile != "" {
+		if err := c.ReloadCRL(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// ReloadCRL reads the CRL file again. The current CRLs are kept if the file
+// cannot be loaded.
+func (c *CertRevocationChecker) ReloadCRL() error {
+	data, err := ioutil.ReadFile(c.CrlFile)
+	if err != nil {
+		return fmt.Errorf("read CRL file %v: %v", c.CrlFile, err)
+	}
+	var ders [][]byte
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type == "X509 CRL" {
+				ders = append(ders, block.Bytes)
+			}
+		}
+	} else {
+		ders = append(ders, data)
+	}
+	if len(ders) == 0 {
+		return fmt.Errorf("no CRL in %v", c.CrlFile)
+	}
+	var crls []*x509.RevocationList
+	for _, der := range ders {
+		crl, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return fmt.Errorf("parse CRL file %v: %v", c.CrlFile, err)
+		}
+		crls = append(crls, crl)
+	}
+
+	c.mu.Lock()
+	c.crls = crls
+	c.mu.Unlock()
+	log.V(1).Infof("Loaded %d CRLs from %v", len(crls), c.CrlFile)
+	return nil
+}
+
+// VerifyPeerCertificate fails if a certificate of the verified chains is
+// revoked.
+func (c *CertRevocationChecker) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		// The root of the chain is trusted as is
+		for i := 0; i < len(chain)-1; i++ {
+			if err := c.checkCert(chain[i], chain[i+1]); err != nil {
+				log.V(1).Infof("Rejected certificate %v: %v", chain[i].Subject, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CertRevocationChecker) checkCert(cert *x509.Certificate, issuer *x509.Certificate) error {
+	if err := c.checkCRL(cert, issuer); err != nil {
+		return err
+	}
+	if c.Ocsp && len(cert.OCSPServer) != 0 {
+		if err := c.checkOCSP(cert, issuer); err != nil {
+			if !c.OcspSoftFail || err == errCertRevoked {
+				return err
+			}
+			log.V(1).Infof("Ignored OCSP failure of certificate %v: %v", cert.Subject, err)
+		}
+	}
+	return nil
+}
+
+func (c *CertRevocationChecker) checkCRL(cert *x509.Certificate, issuer *x509.Certificate) error {
+	c.mu.RLock()
+	crls := c.crls
+	c.mu.RUnlock()
+
+	for _, crl := range crls {
+		if !bytes.Equal(crl.RawIssuer, cert.RawIssuer) {
+			continue
+		}
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			log.V(1).Infof("Ignored CRL of %v: %v", crl.Issuer, err)
+			continue
+		}
+		if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+			log.Warningf("CRL of %v expired on %v", crl.Issuer, crl.NextUpdate)
+		}
+		for _, revoked := range crl.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				return errCertRevoked
+			}
+		}
+	}
+	return nil
+}
+
+func (c *CertRevocationChecker) checkOCSP(cert *x509.Certificate, issuer *x509.Certificate) error {
+	key := string(issuer.RawSubject) + "|" + cert.SerialNumber.String()
+	c.mu.RLock()
+	resp, ok := c.ocspCache[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(ocspExpiry(resp)) {
+		var err error
+		if resp, err = c.queryOCSP(cert, issuer); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		for k, r := range c.ocspCache {
+			if time.Now().After(ocspExpiry(r)) {
+				delete(c.ocspCache, k)
+			}
+		}
+		c.ocspCache[key] = resp
+		c.mu.Unlock()
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return errCertRevoked
+	default:
+		return fmt.Errorf("unknown OCSP status of certificate %v", cert.SerialNumber)
+	}
+}
+
+func ocspExpiry(resp *ocsp.Response) time.Time {
+	if resp.NextUpdate.IsZero() {
+		return resp.ThisUpdate.Add(ocspCacheDuration)
+	}
+	return resp.NextUpdate
+}
+
+// queryOCSP asks the OCSP responders of cert in turn, until one answers.
+func (c *CertRevocationChecker) queryOCSP(cert *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: c.OcspTimeout}
+	for _, server := range cert.OCSPServer {
+		var httpResp *http.Response
+		httpResp, err = client.Post(server, "application/ocsp-request", bytes.NewReader(req))
+		if err != nil {
+			continue
+		}
+		var body []byte
+		body, err = ioutil.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			continue
+		}
+		if httpResp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("OCSP responder %v returned %v", server, httpResp.Status)
+			continue
+		}
+		var resp *ocsp.Response
+		if resp, err = ocsp.ParseResponseForCert(body, cert, issuer); err != nil {
+			continue
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("OCSP request of certificate %v failed: %v", cert.SerialNumber, err)
+}
@@ -1,6 +1,15 @@
 package gnmi
 
 import (
+	"crypto/x509"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/sonic-net/sonic-gnmi/common_utils"
 	"github.com/sonic-net/sonic-gnmi/swsscommon"
 	"github.com/golang/glog"
@@ -11,7 +20,63 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func ClientCertAuthenAndAuthor(ctx context.Context, serviceConfigTableName string) (context.Context, error) {
+// Sources of the client identity in its certificate.
+const (
+	CertIdentityCN     = "cn"      // Subject common name
+	CertIdentitySanDNS = "san_dns" // DNS subject alternative names
+	CertIdentitySanURI = "san_uri" // URI subject alternative names, e.g. SPIFFE IDs
+	CertIdentityOU     = "ou"      // Subject organizational units
+)
+
+// ParseCertIdentitySources parses a comma separated list of certificate
+// identity sources, in order of preference.
+func ParseCertIdentitySources(value string) ([]string, error) {
+	sources := splitPolicyList(value)
+	for _, src := range sources {
+		switch src {
+		case CertIdentityCN, CertIdentitySanDNS, CertIdentitySanURI, CertIdentityOU:
+		default:
+			return nil, fmt.Errorf("invalid certificate identity source %q", src)
+		}
+	}
+	return sources, nil
+}
+
+// certIdentities returns the identities of cert from sources, in order. The
+// common name is used when no source is given.
+func certIdentities(cert *x509.Certificate, sources []string) []string {
+	if len(sources) == 0 {
+		sources = []string{CertIdentityCN}
+	}
+	var ids []string
+	for _, src := range sources {
+		switch src {
+		case CertIdentityCN:
+			ids = append(ids, cert.Subject.CommonName)
+		case CertIdentitySanDNS:
+			ids = append(ids, cert.DNSNames...)
+		case CertIdentitySanURI:
+			for _, uri := range cert.URIs {
+				ids = append(ids, uri.String())
+			}
+		case CertIdentityOU:
+			ids = append(ids, cert.Subject.OrganizationalUnit...)
+		}
+	}
+	var nonEmpty []string
+	for _, id := range ids {
+		if id != "" {
+			nonEmpty = append(nonEmpty, id)
+		}
+	}
+	return nonEmpty
+}
+
+// ClientCertAuthenAndAuthor authenticates the client by the identities of its
+// certificate, taken from sources or the common name by default. The roles
+// are looked up in serviceConfigTableName, or are those of the local user
+// with the identity name when no table is given.
+func ClientCertAuthenAndAuthor(ctx context.Context, serviceConfigTableName string, sources ...string) (context.Context, error) {
 	rc, ctx := common_utils.GetContext(ctx)
 	p, ok := peer.FromContext(ctx)
 	if !ok {
@@ -25,20 +90,24 @@ func ClientCertAuthenAndAuthor(ctx context.Context, serviceConfigTableName strin
 		return ctx, status.Error(codes.Unauthenticated, "could not verify peer certificate")
 	}
 
-	var username string
-
-	username = tlsAuth.State.VerifiedChains[0][0].Subject.CommonName
+	identities := certIdentities(tlsAuth.State.VerifiedChains[0][0], sources)
 
-	if len(username) == 0 {
+	if len(identities) == 0 {
 		return ctx, status.Error(codes.Unauthenticated, "invalid username in certificate common name.")
 	}
 
 	if serviceConfigTableName != "" {
-		if err := PopulateAuthStructByCommonName(username, &rc.Auth, serviceConfigTableName); err != nil {
+		if err := PopulateAuthStructByCertIdentities(identities, &rc.Auth, serviceConfigTableName); err != nil {
 			return ctx, err
 		}
 	} else {
-		if err := PopulateAuthStruct(username, &rc.Auth, nil); err != nil {
+		var err error
+		for _, username := range identities {
+			if err = PopulateAuthStruct(username, &rc.Auth, nil); err == nil {
+				break
+			}
+		}
+		if err != nil {
 			glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
 			return ctx, status.Errorf(codes.Unauthenticated, "")
 		}
@@ -48,6 +117,29 @@ func ClientCertAuthenAndAuthor(ctx context.Context, serviceConfigTableName strin
 }
 
 func PopulateAuthStructByCommonName(certCommonName string, auth *common_utils.AuthInfo, serviceConfigTableName string) error {
+	return PopulateAuthStructByCertIdentities([]string{certCommonName}, auth, serviceConfigTableName)
+}
+
+// certPatternCacheTTL is how long the pattern entries of a cert identity
+// mapping table are cached before being read again from CONFIG_DB.
+var certPatternCacheTTL = 30 * time.Second
+
+type certPatternEntries struct {
+	entries  map[string]map[string]string
+	loadTime time.Time
+}
+
+var certPatternCache = struct {
+	mu     sync.Mutex
+	tables map[string]*certPatternEntries
+}{tables: map[string]*certPatternEntries{}}
+
+// PopulateAuthStructByCertIdentities sets the roles of the client from the
+// config table entry of the first identity having one. Entries are keyed by
+// identity, or by a pattern when their "match" field is "glob" or "regex";
+// the roles of all the patterns matching the first matched identity are
+// merged. The "role" field holds a comma separated list of roles.
+func PopulateAuthStructByCertIdentities(identities []string, auth *common_utils.AuthInfo, serviceConfigTableName string) error {
 	if serviceConfigTableName == "" {
 		return status.Errorf(codes.Unauthenticated, "Service config table name should not be empty")
 	}
@@ -56,21 +148,115 @@ func PopulateAuthStructByCommonName(certCommonName string, auth *common_utils.Au
 	defer swsscommon.DeleteConfigDBConnector_Native(configDbConnector.ConfigDBConnector_Native)
 	configDbConnector.Connect(false)
 
-	var fieldValuePairs = configDbConnector.Get_entry(serviceConfigTableName, certCommonName)
-	if fieldValuePairs.Size() > 0 {
-		if fieldValuePairs.Has_key("role") {
-			var role = fieldValuePairs.Get("role")
-			auth.Roles = []string{role}
+	var patterns map[string]map[string]string
+	for _, id := range identities {
+		auth.Roles = certEntryRoles(getCertEntry(configDbConnector, serviceConfigTableName, id))
+		if len(auth.Roles) == 0 {
+			if patterns == nil {
+				patterns = loadCertPatterns(serviceConfigTableName)
+			}
+			auth.Roles = matchCertPatterns(patterns, id)
+		}
+		if len(auth.Roles) != 0 {
+			glog.V(5).Infof("cert identity %s mapped to roles %v", id, auth.Roles)
+			return nil
 		}
-	} else {
-		glog.Warningf("Failed to retrieve cert common name mapping; %s", certCommonName)
 	}
+	glog.Warningf("Failed to retrieve cert identity mapping; %v", identities)
+	return status.Errorf(codes.Unauthenticated, "Invalid cert identity:'%s', not a trusted cert identity.", strings.Join(identities, ","))
+}
 
-	swsscommon.DeleteFieldValueMap(fieldValuePairs)
+// getCertEntry returns the fields of the entry keyed by id, or nil if there
+// is none or it is a pattern entry.
+func getCertEntry(configDbConnector *swsscommon.ConfigDBConnector, table string, id string) map[string]string {
+	var fieldValuePairs = configDbConnector.Get_entry(table, id)
+	defer swsscommon.DeleteFieldValueMap(fieldValuePairs)
 
-	if len(auth.Roles) == 0 {
-		return status.Errorf(codes.Unauthenticated, "Invalid cert cname:'%s', not a trusted cert common name.", certCommonName)
-	} else {
+	if fieldValuePairs.Size() == 0 || fieldValuePairs.Has_key("match") {
 		return nil
 	}
-}
\ No newline at end of file
+	fv := map[string]string{}
+	for _, field := range []string{"role", "role@"} {
+		if fieldValuePairs.Has_key(field) {
+			fv[field] = fieldValuePairs.Get(field)
+		}
+	}
+	return fv
+}
+
+// loadCertPatterns returns the pattern entries of table, read again from
+// CONFIG_DB once they are older than certPatternCacheTTL. The previous
+// entries are kept if the table cannot be read.
+func loadCertPatterns(table string) map[string]map[string]string {
+	certPatternCache.mu.Lock()
+	defer certPatternCache.mu.Unlock()
+
+	cached, ok := certPatternCache.tables[table]
+	if ok && time.Since(cached.loadTime) < certPatternCacheTTL {
+		return cached.entries
+	}
+	entries, err := readConfigDbTable(table)
+	if err != nil {
+		glog.Warningf("Failed to read cert identity mapping table %s; %v", table, err)
+		if ok {
+			return cached.entries
+		}
+		return map[string]map[string]string{}
+	}
+	patterns := map[string]map[string]string{}
+	for key, fv := range entries {
+		if fv["match"] != "" {
+			patterns[key] = fv
+		}
+	}
+	certPatternCache.tables[table] = &certPatternEntries{entries: patterns, loadTime: time.Now()}
+	return patterns
+}
+
+func certEntryRoles(fv map[string]string) []string {
+	roles := splitPolicyList(fv["role"])
+	if len(roles) == 0 {
+		// List fields of CONFIG_DB are suffixed by "@"
+		roles = splitPolicyList(fv["role@"])
+	}
+	return roles
+}
+
+// matchCertPatterns returns the roles of the pattern entries matching id.
+func matchCertPatterns(entries map[string]map[string]string, id string) []string {
+	var patterns []string
+	for key, fv := range entries {
+		if fv["match"] != "" {
+			patterns = append(patterns, key)
+		}
+	}
+	sort.Strings(patterns)
+
+	var roles []string
+	for _, pattern := range patterns {
+		fv := entries[pattern]
+		var matched bool
+		var err error
+		switch fv["match"] {
+		case "glob":
+			matched, err = path.Match(pattern, id)
+		case "regex":
+			matched, err = regexp.MatchString("^(?:"+pattern+")$", id)
+		default:
+			err = fmt.Errorf("unknown match type %q", fv["match"])
+		}
+		if err != nil {
+			glog.Warningf("Invalid cert identity pattern %s; %v", pattern, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		for _, role := range certEntryRoles(fv) {
+			if !containsString(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
@@ -27,6 +27,8 @@ type Client struct {
 	mu        sync.RWMutex
 	q         *queue.PriorityQueue
 	subscribe *gnmipb.SubscriptionList
+	aliases   *sdc.Aliases
+	authorize func(req *AuthzRequest) error
 	// Wait for all sub go routine to finish
 	w     sync.WaitGroup
 	fatal bool
@@ -54,6 +56,10 @@ func (c *Client) setLogLevel(lvl int) {
 	c.logLevel = lvl
 }
 
+func (c *Client) setAuthorizer(authorize func(req *AuthzRequest) error) {
+	c.authorize = authorize
+}
+
 func (c *Client) setConnectionManager(threshold int) {
 	if connectionManager != nil && threshold == connectionManager.GetThreshold() {
 		return
@@ -92,8 +98,9 @@ func (c *Client) populateDbPathSubscrition(sublist *gnmipb.SubscriptionList) ([]
 }
 
 // Run starts the subscribe client. The first message received must be a
-// SubscriptionList. Once the client is started, it will run until the stream
-// is closed or the schedule completes. For Poll queries the Run will block
+// SubscriptionList, optionally preceded by client-defined aliases. Once the
+// client is started, it will run until the stream is closed or the schedule
+// completes. For Poll queries the Run will block
 // internally after sync until a Poll request is made to the server.
 func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 	defer log.V(1).Infof("Client %s shutdown", c)
@@ -122,12 +129,30 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 
 	log.V(2).Infof("Client %s recieved initial query %v", c, query)
 
+	c.aliases = sdc.NewAliases()
+	for query.GetAliases() != nil {
+		if err = c.aliases.AddList(query.GetAliases()); err != nil {
+			return grpc.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		query, err = stream.Recv()
+		c.recvMsg++
+		if err != nil {
+			if err == io.EOF {
+				return grpc.Errorf(codes.Aborted, "stream EOF received before init")
+			}
+			return grpc.Errorf(grpc.Code(err), "received error from client")
+		}
+	}
+
 	c.subscribe = query.GetSubscribe()
 	extensions := query.GetExtension()
 
 	if c.subscribe == nil {
 		return grpc.Errorf(codes.InvalidArgument, "first message must be SubscriptionList: %q", query)
 	}
+	if c.subscribe.GetUseAliases() {
+		c.aliases.UseServerAliases()
+	}
 
 	prefix := c.subscribe.GetPrefix()
 	origin := prefix.GetOrigin()
@@ -146,6 +171,13 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 		return status.Error(codes.InvalidArgument, "Origin conflict between prefix and paths")
 	}
 
+	if c.authorize != nil {
+		authzReq := &AuthzRequest{Rpc: rpcGnmiSubscribe, Origin: origin, Prefix: prefix, Paths: paths}
+		if err = c.authorize(authzReq); err != nil {
+			return err
+		}
+	}
+
 	if connectionKey, valid = connectionManager.Add(c.addr, query.String()); !valid {
 		return grpc.Errorf(codes.Unavailable, "Server connections are at capacity.")
 	}
@@ -158,32 +190,13 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 
 	log.V(3).Infof("mode=%v, origin=%q, target=%q", mode, origin, target)
 
-	if origin == "openconfig" {
-		dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions, sdc.TranslWildcardOption{})
-	} else if IsNativeOrigin(origin) {
-		dc, err = sdc.NewMixedDbClient(paths, prefix, origin, gnmipb.Encoding_JSON_IETF, "")
-	} else if len(origin) != 0 {
-		return grpc.Errorf(codes.Unimplemented, "Unsupported origin: %s", origin)
-	} else if target == "" {
-		// This and subsequent conditions handle target based path identification
-		// when origin == "". As per the spec it should have been treated as "openconfig".
-		// But we take a deviation and stick to legacy logic for backward compatibility
-		return grpc.Errorf(codes.Unimplemented, "Empty target data not supported")
-	} else if target == "OTHERS" {
-		dc, err = sdc.NewNonDbClient(paths, prefix)
-	} else if ((target == "EVENTS") && (mode == gnmipb.SubscriptionList_STREAM)) {
-		dc, err = sdc.NewEventClient(paths, prefix, c.logLevel)
-	} else if _, ok, _, _ := sdc.IsTargetDb(target); ok {
-		dc, err = sdc.NewDbClient(paths, prefix)
-	} else {
-		/* For any other target or no target create new Transl Client. */
-		dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions, sdc.TranslWildcardOption{})
-	}
-	defer dc.Close()
-
-	if err != nil {
+	dc, err = sdc.NewSubscribeClient(ctx, prefix, paths, origin, extensions, c.logLevel)
+	if _, ok := err.(*sdc.UnsupportedSubscribeError); ok {
+		return grpc.Errorf(codes.Unimplemented, "%v", err)
+	} else if err != nil {
 		return grpc.Errorf(codes.NotFound, "%v", err)
 	}
+	defer dc.Close()
 
 	switch mode {
 	case gnmipb.SubscriptionList_STREAM:
@@ -210,6 +223,10 @@ func (c *Client) Run(stream gnmipb.GNMI_SubscribeServer) (err error) {
 	c.Close()
 	// Wait until all child go routines exited
 	c.w.Wait()
+	if err == nil {
+		// ONCE subscription completed
+		return nil
+	}
 	return grpc.Errorf(codes.InvalidArgument, "%s", err)
 }
 
@@ -264,6 +281,14 @@ func (c *Client) recv(stream gnmipb.GNMI_SubscribeServer) {
 		case nil:
 		}
 
+		if aliases := event.GetAliases(); aliases != nil {
+			log.V(3).Infof("Client %s received Aliases event: %v", c, event)
+			if err := c.aliases.AddList(aliases); err != nil {
+				log.V(1).Infof("Client %s invalid aliases: %v", c, err)
+			}
+			continue
+		}
+
 		if c.subscribe.Mode == gnmipb.SubscriptionList_POLL {
 			log.V(3).Infof("Client %s received Poll event: %v", c, event)
 			if _, ok := event.Request.(*gnmipb.SubscribeRequest_Poll); !ok {
@@ -276,7 +301,8 @@ func (c *Client) recv(stream gnmipb.GNMI_SubscribeServer) {
 	}
 }
 
-// send runs until process Queue returns an error.
+// send runs until process Queue returns an error, or until the sync response
+// of a ONCE subscription is sent.
 func (c *Client) send(stream gnmipb.GNMI_SubscribeServer, dc sdc.Client) error {
 	for {
 		var val *sdc.Value
@@ -296,7 +322,16 @@ func (c *Client) send(stream gnmipb.GNMI_SubscribeServer, dc sdc.Client) error {
 
 		switch v := items[0].(type) {
 		case sdc.Value:
-			if resp, err = sdc.ValToResp(v); err != nil {
+			if aliasResp := sdc.ValToAliasResp(v, c.aliases); aliasResp != nil {
+				c.sendMsg++
+				if err = stream.Send(aliasResp); err != nil {
+					log.V(1).Infof("Client %s sending error:%v", c, err)
+					c.errors++
+					dc.FailedSend()
+					return err
+				}
+			}
+			if resp, err = sdc.ValToResp(v, c.aliases); err != nil {
 				c.errors++
 				return err
 			}
@@ -317,5 +352,11 @@ func (c *Client) send(stream gnmipb.GNMI_SubscribeServer, dc sdc.Client) error {
 
 		dc.SentOne(val)
 		log.V(5).Infof("Client %s done sending, msg count %d, msg %v", c, c.sendMsg, resp)
+
+		// The stream of a ONCE subscription ends with its sync response
+		if c.subscribe.GetMode() == gnmipb.SubscriptionList_ONCE && resp.GetSyncResponse() {
+			log.V(1).Infof("Client %s ONCE subscription completed", c)
+			return nil
+		}
 	}
 }
@@ -39,6 +39,9 @@ func (srv *Server) GetSubscribePreferences(req *spb_gnoi.SubscribePreferencesReq
 	if err != nil {
 		return err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcDebugSubscribePreferences}); err != nil {
+		return err
+	}
 
 	translPaths := make([]translib.IsSubscribePath, 0, len(req.GetPath()))
 	for i, p := range req.GetPath() {
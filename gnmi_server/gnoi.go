@@ -2,21 +2,18 @@ package gnmi
 
 import (
 	"context"
-	"errors"
-	"os"
 	gnoi_system_pb "github.com/openconfig/gnoi/system"
 	log "github.com/golang/glog"
 	"time"
 	spb "github.com/sonic-net/sonic-gnmi/proto/gnoi"
 	transutil "github.com/sonic-net/sonic-gnmi/transl_utils"
-	io "io/ioutil"
 	ssc "github.com/sonic-net/sonic-gnmi/sonic_service_client"
 	spb_jwt "github.com/sonic-net/sonic-gnmi/proto/gnoi/jwt"
-	"github.com/sonic-net/sonic-gnmi/common_utils"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/codes"
 	"os/user"
 	"encoding/json"
+	"syscall"
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -41,23 +38,65 @@ func KillOrRestartProcess(restart bool, serviceName string) error {
 	return err
 }
 
+// SIGNAL_ABRT of KillProcessRequest, missing from the gNOI version in use.
+const killProcessSignalAbrt gnoi_system_pb.KillProcessRequest_Signal = 4
+
+// Unix signals sent for the KillProcessRequest signals.
+var killProcessSignals = map[gnoi_system_pb.KillProcessRequest_Signal]syscall.Signal{
+	gnoi_system_pb.KillProcessRequest_SIGNAL_TERM: syscall.SIGTERM,
+	gnoi_system_pb.KillProcessRequest_SIGNAL_KILL: syscall.SIGKILL,
+	gnoi_system_pb.KillProcessRequest_SIGNAL_HUP:  syscall.SIGHUP,
+	killProcessSignalAbrt:                         syscall.SIGABRT,
+}
+
+// SignalProcess sends signal to the process pid of container, or to the main
+// process of container if pid is 0. Host processes cannot be signalled through
+// gNOI, the container is always required.
+func SignalProcess(container string, pid uint32, signal syscall.Signal) error {
+	sc, err := ssc.NewDbusClient()
+	if err != nil {
+		return err
+	}
+	log.V(2).Infof("Sending signal %v to process %d of %q...", signal, pid, container)
+	err = sc.KillProcess(container, pid, int(signal))
+	if err != nil {
+		log.V(2).Infof("Failed to send signal %v to process %d of %q: %v", signal, pid, container, err)
+	}
+	return err
+}
+
 func (srv *Server) KillProcess(ctx context.Context, req *gnoi_system_pb.KillProcessRequest) (*gnoi_system_pb.KillProcessResponse, error) {
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
             return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemKillProcess, Write: true}); err != nil {
+		return nil, err
+	}
 
 	serviceName := req.GetName()
 	restart := req.GetRestart()
-        if req.GetPid() != 0 {
-            return nil, status.Errorf(codes.Unimplemented, "Pid option is not implemented")
-        }
-        if req.GetSignal() != gnoi_system_pb.KillProcessRequest_SIGNAL_TERM {
-            return nil, status.Errorf(codes.Unimplemented, "KillProcess only supports SIGNAL_TERM (option 1) for graceful process termination. Please specify SIGNAL_TERM")
-        }
+	signal, ok := killProcessSignals[req.GetSignal()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Unsupported signal %v", req.GetSignal())
+	}
+	if serviceName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Process name is required")
+	}
+	// The init process of the container may not be signalled by pid
+	if pid := req.GetPid(); pid == 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "Signalling process %d is not allowed", pid)
+	}
 	log.V(1).Info("gNOI: KillProcess with optional restart")
 	log.V(1).Info("Request: ", req)
-	err = KillOrRestartProcess(restart, serviceName)
+	if req.GetPid() == 0 && req.GetSignal() == gnoi_system_pb.KillProcessRequest_SIGNAL_TERM {
+		// Graceful termination stops or restarts the service
+		err = KillOrRestartProcess(restart, serviceName)
+	} else if restart {
+		return nil, status.Errorf(codes.InvalidArgument, "Restart is only supported for services with SIGNAL_TERM")
+	} else {
+		err = SignalProcess(serviceName, req.GetPid(), signal)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -76,23 +115,16 @@ func RebootSystem(fileName string) error {
 }
 
 func (srv *Server) Reboot(ctx context.Context, req *gnoi_system_pb.RebootRequest) (*gnoi_system_pb.RebootResponse, error) {
-	fileName := common_utils.GNMI_WORK_PATH + "/config_db.json.tmp"
-
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemReboot, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Reboot")
 	log.V(1).Info("Request:", req)
-	log.V(1).Info("Reboot system now, delay is ignored...")
-	// TODO: Support GNOI reboot delay
-	// Delay in nanoseconds before issuing reboot.
-	// https://github.com/openconfig/gnoi/blob/master/system/system.proto#L102-L115
-	config_db_json, err := io.ReadFile(fileName)
-	if errors.Is(err, os.ErrNotExist) {
-		fileName = ""
-	}
-	err = RebootSystem(string(config_db_json))
+	err = rebooter.schedule(req)
 	if err != nil {
 		return nil, err
 	}
@@ -100,65 +132,69 @@ func (srv *Server) Reboot(ctx context.Context, req *gnoi_system_pb.RebootRequest
 	return &resp, nil
 }
 
-// TODO: Support GNOI RebootStatus
 func (srv *Server) RebootStatus(ctx context.Context, req *gnoi_system_pb.RebootStatusRequest) (*gnoi_system_pb.RebootStatusResponse, error) {
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemRebootStatus}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: RebootStatus")
-	return nil, status.Errorf(codes.Unimplemented, "")
+	if len(req.GetSubcomponents()) != 0 {
+		return nil, status.Errorf(codes.Unimplemented, "Reboot status of subcomponents is not supported")
+	}
+	return rebooter.status(), nil
 }
 
-// TODO: Support GNOI CancelReboot
 func (srv *Server) CancelReboot(ctx context.Context, req *gnoi_system_pb.CancelRebootRequest) (*gnoi_system_pb.CancelRebootResponse, error) {
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemCancelReboot, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: CancelReboot")
-	return nil, status.Errorf(codes.Unimplemented, "")
-}
-func (srv *Server) Ping(req *gnoi_system_pb.PingRequest, rs gnoi_system_pb.System_PingServer) error {
-	ctx := rs.Context()
-	_, err := authenticate(srv.config, ctx)
-	if err != nil {
-		return err
+	if len(req.GetSubcomponents()) != 0 {
+		return nil, status.Errorf(codes.Unimplemented, "Reboot of subcomponents is not supported")
 	}
-	log.V(1).Info("gNOI: Ping")
-	return status.Errorf(codes.Unimplemented, "")
-}
-func (srv *Server) Traceroute(req *gnoi_system_pb.TracerouteRequest, rs gnoi_system_pb.System_TracerouteServer) error {
-	ctx := rs.Context()
-	_, err := authenticate(srv.config, ctx)
-	if err != nil {
-		return err
+	if err = rebooter.cancel(req.GetMessage()); err != nil {
+		return nil, err
 	}
-	log.V(1).Info("gNOI: Traceroute")
-	return status.Errorf(codes.Unimplemented, "")
+	return &gnoi_system_pb.CancelRebootResponse{}, nil
 }
 func (srv *Server) SetPackage(rs gnoi_system_pb.System_SetPackageServer) error {
 	ctx := rs.Context()
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemSetPackage, Write: true}); err != nil {
+		return err
+	}
 	log.V(1).Info("gNOI: SetPackage")
 	return status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) SwitchControlProcessor(ctx context.Context, req *gnoi_system_pb.SwitchControlProcessorRequest) (*gnoi_system_pb.SwitchControlProcessorResponse, error) {
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemSwitchControlProc, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: SwitchControlProcessor")
 	return nil, status.Errorf(codes.Unimplemented, "")
 }
 func (srv *Server) Time(ctx context.Context, req *gnoi_system_pb.TimeRequest) (*gnoi_system_pb.TimeResponse, error) {
-	_, err := authenticate(srv.config, ctx)
+	ctx, err := authenticate(srv.config, ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemTime}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Time")
 	var tm gnoi_system_pb.TimeResponse
 	tm.Time = uint64(time.Now().UnixNano())
@@ -196,6 +232,9 @@ func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*s
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcJwtRefresh}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic Refresh")
 
 	if !srv.config.UserAuth.Enabled("jwt") {
@@ -208,9 +247,7 @@ func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*s
 	}
 
 	claims := &Claims{}
-	jwt.ParseWithClaims(token.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return hmacSampleSecret, nil
-	})
+	jwt.ParseWithClaims(token.AccessToken, claims, jwtKeys.keyFunc)
 	if time.Unix(claims.ExpiresAt, 0).Sub(time.Now()) > JwtRefreshInt {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid JWT Token")
 	}
@@ -219,11 +256,96 @@ func (srv *Server) Refresh(ctx context.Context, req *spb_jwt.RefreshRequest) (*s
 
 }
 
+func (srv *Server) Revoke(ctx context.Context, req *spb_jwt.RevokeRequest) (*spb_jwt.RevokeResponse, error) {
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcJwtRevoke, Write: true}); err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: Sonic Revoke")
+
+	if !srv.config.UserAuth.Enabled("jwt") {
+		return nil, status.Errorf(codes.Unimplemented, "")
+	}
+	if req.GetJti() == "" && req.GetUsername() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "jti or username must be set")
+	}
+	// Users outside the admin role only revoke their own sessions
+	owner, err := jwtSessionOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" {
+		if req.GetUsername() != "" && req.GetUsername() != owner {
+			return nil, status.Errorf(codes.PermissionDenied, "user %v cannot revoke the sessions of %v", owner, req.GetUsername())
+		}
+		if req.GetJti() != "" {
+			user, err := jwtSessions.user(req.GetJti())
+			if err != nil {
+				return nil, err
+			}
+			if user != "" && user != owner {
+				return nil, status.Errorf(codes.PermissionDenied, "user %v cannot revoke the session %v", owner, req.GetJti())
+			}
+		}
+	}
+
+	revoked, err := jwtSessions.revoke(req.GetJti(), req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	// Revoking an already revoked session is not an error
+	if len(revoked) == 0 && req.GetJti() != "" && req.GetUsername() == "" {
+		if wasRevoked, _ := jwtSessions.isRevoked(req.GetJti()); !wasRevoked {
+			return nil, status.Errorf(codes.NotFound, "JWT session %s not found", req.GetJti())
+		}
+	}
+	return &spb_jwt.RevokeResponse{Sessions: jwtSessionsToProto(revoked)}, nil
+}
+
+func (srv *Server) ListSessions(ctx context.Context, req *spb_jwt.ListSessionsRequest) (*spb_jwt.ListSessionsResponse, error) {
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcJwtListSessions}); err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: Sonic ListSessions")
+
+	if !srv.config.UserAuth.Enabled("jwt") {
+		return nil, status.Errorf(codes.Unimplemented, "")
+	}
+
+	// Users outside the admin role only list their own sessions
+	owner, err := jwtSessionOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username := req.GetUsername()
+	if owner != "" {
+		if username != "" && username != owner {
+			return nil, status.Errorf(codes.PermissionDenied, "user %v cannot list the sessions of %v", owner, username)
+		}
+		username = owner
+	}
+	sessions, err := jwtSessions.list(username)
+	if err != nil {
+		return nil, err
+	}
+	return &spb_jwt.ListSessionsResponse{Sessions: jwtSessionsToProto(sessions)}, nil
+}
+
 func (srv *Server) ClearNeighbors(ctx context.Context, req *spb.ClearNeighborsRequest) (*spb.ClearNeighborsResponse, error) {
     ctx, err := authenticate(srv.config, ctx)
     if err != nil {
         return nil, err
     }
+    if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicClearNeighbors, Write: true}); err != nil {
+    	return nil, err
+    }
     log.V(1).Info("gNOI: Sonic ClearNeighbors")
     log.V(1).Info("Request: ", req)
 
@@ -256,6 +378,9 @@ func (srv *Server) CopyConfig(ctx context.Context, req *spb.CopyConfigRequest) (
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicCopyConfig, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic CopyConfig")
 	
 	resp := &spb.CopyConfigResponse{
@@ -287,6 +412,9 @@ func (srv *Server) ShowTechsupport(ctx context.Context, req *spb.TechsupportRequ
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicShowTechsupport}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic ShowTechsupport")
 	
 	resp := &spb.TechsupportResponse{
@@ -319,6 +447,9 @@ func (srv *Server) ImageInstall(ctx context.Context, req *spb.ImageInstallReques
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicImageInstall, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic ImageInstall")
 	
 	resp := &spb.ImageInstallResponse{
@@ -351,6 +482,9 @@ func (srv *Server) ImageRemove(ctx context.Context, req *spb.ImageRemoveRequest)
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicImageRemove, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic ImageRemove")
 	
 	resp := &spb.ImageRemoveResponse{
@@ -383,6 +517,9 @@ func (srv *Server) ImageDefault(ctx context.Context, req *spb.ImageDefaultReques
 	if err != nil {
 		return nil, err
 	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSonicImageDefault, Write: true}); err != nil {
+		return nil, err
+	}
 	log.V(1).Info("gNOI: Sonic ImageDefault")
 	
 	resp := &spb.ImageDefaultResponse{
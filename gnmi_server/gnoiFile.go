@@ -0,0 +1,339 @@
+package gnmi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/golang/glog"
+	"github.com/openconfig/gnoi/common"
+	gnoi_file_pb "github.com/openconfig/gnoi/file"
+	"github.com/openconfig/gnoi/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Size of the file chunks streamed by Get.
+const fileChunkSize = 64 * 1024
+
+// Maximum size of the files written by Put.
+var maxFilePutSize int64 = 1024 * 1024 * 1024
+
+// resolveFile returns the absolute path of name with its symbolic links
+// resolved, as far as it exists, if it is within the directories dirs.
+func resolveFile(dirs []string, name string) (string, error) {
+	resolved := filepath.Clean(name)
+	if r, err := filepath.EvalSymlinks(resolved); err == nil {
+		resolved = r
+	} else if r, err := filepath.EvalSymlinks(filepath.Dir(resolved)); err == nil {
+		resolved = filepath.Join(r, filepath.Base(resolved))
+	}
+	if !matchFileDirs(dirs, resolved) {
+		return "", status.Errorf(codes.PermissionDenied, "Access to %q is not allowed", name)
+	}
+	return resolved, nil
+}
+
+// FileServer serves the gNOI File service of a Server, whose Get method
+// conflicts with the gNMI one.
+type FileServer struct {
+	srv *Server
+	gnoi_file_pb.UnimplementedFileServer
+}
+
+// authorizeFile authenticates and authorizes the file operation on name,
+// and returns its resolved path. The requested path is authorized before
+// it is looked up, so that callers cannot probe the files they may not
+// access, and the resolved path again once its links are followed.
+func (fs *FileServer) authorizeFile(ctx context.Context, rpc string, write bool, name string) (string, error) {
+	ctx, err := authenticate(fs.srv.config, ctx)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(name) {
+		return "", status.Errorf(codes.InvalidArgument, "Path %q is not absolute", name)
+	}
+	name = filepath.Clean(name)
+	if err = authorize(fs.srv.config, ctx, &AuthzRequest{Rpc: rpc, Write: write, Files: []string{name}}); err != nil {
+		return "", err
+	}
+	resolved, err := resolveFile(fs.srv.config.FileDirs, name)
+	if err != nil {
+		return "", err
+	}
+	if resolved != name {
+		if err = authorize(fs.srv.config, ctx, &AuthzRequest{Rpc: rpc, Write: write, Files: []string{resolved}}); err != nil {
+			return "", err
+		}
+	}
+	return resolved, nil
+}
+
+func fileError(err error) error {
+	switch {
+	case os.IsNotExist(err):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case os.IsPermission(err):
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	return status.Errorf(codes.Internal, "%v", err)
+}
+
+// gNOI permissions are octal digits written as a decimal number, e.g. 644.
+func filePermissions(mode os.FileMode) uint32 {
+	perm, _ := strconv.ParseUint(strconv.FormatUint(uint64(mode.Perm()), 8), 10, 32)
+	return uint32(perm)
+}
+
+func fileMode(permissions uint32) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(strconv.FormatUint(uint64(permissions), 10), 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, status.Errorf(codes.InvalidArgument, "Invalid permissions %d", permissions)
+	}
+	return os.FileMode(mode), nil
+}
+
+// processUmask returns the umask of the server process.
+func processUmask() uint32 {
+	data, err := ioutil.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && fields[0] == "Umask:" {
+			umask, _ := strconv.ParseUint(fields[1], 8, 32)
+			return filePermissions(os.FileMode(umask))
+		}
+	}
+	return 0
+}
+
+func (fs *FileServer) Get(req *gnoi_file_pb.GetRequest, stream gnoi_file_pb.File_GetServer) error {
+	name, err := fs.authorizeFile(stream.Context(), rpcFileGet, false, req.GetRemoteFile())
+	if err != nil {
+		return err
+	}
+	log.V(1).Infof("gNOI: File Get %v", name)
+	f, err := os.Open(name)
+	if err != nil {
+		return fileError(err)
+	}
+	defer f.Close()
+	if fi, err := f.Stat(); err == nil && !fi.Mode().IsRegular() {
+		return status.Errorf(codes.InvalidArgument, "%v is not a regular file", name)
+	}
+
+	h := sha256.New()
+	buf := make([]byte, fileChunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			resp := &gnoi_file_pb.GetResponse{Response: &gnoi_file_pb.GetResponse_Contents{Contents: buf[:n]}}
+			if serr := stream.Send(resp); serr != nil {
+				return serr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fileError(err)
+		}
+	}
+	return stream.Send(&gnoi_file_pb.GetResponse{Response: &gnoi_file_pb.GetResponse_Hash{
+		Hash: &types.HashType{Method: types.HashType_SHA256, Hash: h.Sum(nil)},
+	}})
+}
+
+func (fs *FileServer) Put(stream gnoi_file_pb.File_PutServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	open := req.GetOpen()
+	if open == nil {
+		return status.Errorf(codes.InvalidArgument, "First Put request must open the file")
+	}
+	name, err := fs.authorizeFile(stream.Context(), rpcFilePut, true, open.GetRemoteFile())
+	if err != nil {
+		return err
+	}
+	mode, err := fileMode(open.GetPermissions())
+	if err != nil {
+		return err
+	}
+	log.V(1).Infof("gNOI: File Put %v", name)
+
+	// Write to a temporary file first, not to lose the current one on failure
+	tmp, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	if err != nil {
+		return fileError(err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	var size int64
+	hashes := map[types.HashType_HashMethod]hash.Hash{
+		types.HashType_SHA256: sha256.New(),
+		types.HashType_SHA512: sha512.New(),
+		types.HashType_MD5:    md5.New(),
+	}
+	for {
+		req, err = stream.Recv()
+		if err == io.EOF {
+			return status.Errorf(codes.InvalidArgument, "Put of %v ended without hash", name)
+		}
+		if err != nil {
+			return err
+		}
+		if contents := req.GetContents(); contents != nil {
+			if size += int64(len(contents)); size > maxFilePutSize {
+				return status.Errorf(codes.ResourceExhausted, "Put of %v exceeds %d bytes", name, maxFilePutSize)
+			}
+			if _, err = tmp.Write(contents); err != nil {
+				return fileError(err)
+			}
+			for _, h := range hashes {
+				h.Write(contents)
+			}
+			continue
+		}
+		if hashType := req.GetHash(); hashType != nil {
+			h, ok := hashes[hashType.GetMethod()]
+			if !ok {
+				return status.Errorf(codes.InvalidArgument, "Unsupported hash method %v", hashType.GetMethod())
+			}
+			if !bytes.Equal(h.Sum(nil), hashType.GetHash()) {
+				return status.Errorf(codes.DataLoss, "Hash mismatch of %v", name)
+			}
+			break
+		}
+		return status.Errorf(codes.InvalidArgument, "Unexpected Put request %v", req)
+	}
+
+	if err = tmp.Chmod(mode); err == nil {
+		err = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), name)
+	}
+	if err != nil {
+		return fileError(err)
+	}
+	return stream.SendAndClose(&gnoi_file_pb.PutResponse{})
+}
+
+func (fs *FileServer) Stat(ctx context.Context, req *gnoi_file_pb.StatRequest) (*gnoi_file_pb.StatResponse, error) {
+	name, err := fs.authorizeFile(ctx, rpcFileStat, false, req.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	log.V(1).Infof("gNOI: File Stat %v", name)
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, fileError(err)
+	}
+	infos := []os.FileInfo{fi}
+	dir := filepath.Dir(name)
+	if fi.IsDir() {
+		// Directories are described by their entries
+		if infos, err = ioutil.ReadDir(name); err != nil {
+			return nil, fileError(err)
+		}
+		dir = name
+	}
+	umask := processUmask()
+	resp := &gnoi_file_pb.StatResponse{}
+	for _, fi := range infos {
+		resp.Stats = append(resp.Stats, &gnoi_file_pb.StatInfo{
+			Path:         filepath.Join(dir, fi.Name()),
+			LastModified: uint64(fi.ModTime().UnixNano()),
+			Permissions:  filePermissions(fi.Mode()),
+			Size:         uint64(fi.Size()),
+			Umask:        umask,
+		})
+	}
+	return resp, nil
+}
+
+func (fs *FileServer) Remove(ctx context.Context, req *gnoi_file_pb.RemoveRequest) (*gnoi_file_pb.RemoveResponse, error) {
+	name, err := fs.authorizeFile(ctx, rpcFileRemove, true, req.GetRemoteFile())
+	if err != nil {
+		return nil, err
+	}
+	log.V(1).Infof("gNOI: File Remove %v", name)
+	fi, err := os.Lstat(name)
+	if err != nil {
+		return nil, fileError(err)
+	}
+	if fi.IsDir() {
+		return nil, status.Errorf(codes.InvalidArgument, "%v is a directory", name)
+	}
+	if err = os.Remove(name); err != nil {
+		return nil, fileError(err)
+	}
+	return &gnoi_file_pb.RemoveResponse{}, nil
+}
+
+func (fs *FileServer) TransferToRemote(ctx context.Context, req *gnoi_file_pb.TransferToRemoteRequest) (*gnoi_file_pb.TransferToRemoteResponse, error) {
+	name, err := fs.authorizeFile(ctx, rpcFileTransferToRemote, false, req.GetLocalPath())
+	if err != nil {
+		return nil, err
+	}
+	remote := req.GetRemoteDownload()
+	url := remote.GetPath()
+	switch remote.GetProtocol() {
+	case common.RemoteDownload_HTTP:
+		if !strings.HasPrefix(url, "http://") {
+			url = "http://" + url
+		}
+	case common.RemoteDownload_HTTPS:
+		if !strings.HasPrefix(url, "https://") {
+			url = "https://" + url
+		}
+	default:
+		return nil, status.Errorf(codes.Unimplemented, "Transfer protocol %v is not supported", remote.GetProtocol())
+	}
+	log.V(1).Infof("gNOI: File TransferToRemote %v to %v", name, url)
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fileError(err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, url, io.TeeReader(f, h))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if creds := remote.GetCredentials(); creds != nil {
+		if creds.GetHashed() != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Hashed password is not supported")
+		}
+		httpReq.SetBasicAuth(creds.GetUsername(), creds.GetCleartext())
+	}
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "Transfer to %v failed: %v", url, err)
+	}
+	httpResp.Body.Close()
+	if httpResp.StatusCode/100 != 2 {
+		return nil, status.Errorf(codes.Unavailable, "Transfer to %v failed: %v", url, httpResp.Status)
+	}
+	return &gnoi_file_pb.TransferToRemoteResponse{
+		Hash: &types.HashType{Method: types.HashType_SHA256, Hash: h.Sum(nil)},
+	}, nil
+}
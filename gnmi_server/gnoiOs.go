@@ -0,0 +1,267 @@
+package gnmi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	log "github.com/golang/glog"
+	gnoi_os_pb "github.com/openconfig/gnoi/os"
+	gnoi_system_pb "github.com/openconfig/gnoi/system"
+	ssc "github.com/sonic-net/sonic-gnmi/sonic_service_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata key of the expected hex SHA-256 of the image sent to
+// Install, as TransferEnd carries no hash. Images sent without it are
+// installed unverified, unless Config.RequireImageHash is set.
+const imageHashMetadataKey = "image-sha256"
+
+// Received bytes between the TransferProgress messages of Install.
+const imageProgressInterval = 1 << 20
+
+// Maximum size of the images received by Install, unless configured.
+const DefaultMaxImageSize = 4 * 1024 * 1024 * 1024
+
+// A single Install may run at a time.
+var imageInstallMu sync.Mutex
+
+// sonicImages lists the installed SONiC images, as reported by the host.
+type sonicImages struct {
+	Current   string   `json:"current"`
+	Next      string   `json:"next"`
+	Available []string `json:"available"`
+}
+
+func (images *sonicImages) has(version string) bool {
+	return containsString(images.Available, version)
+}
+
+func listSonicImages(sc ssc.Service) (*sonicImages, error) {
+	output, err := sc.ListImages()
+	if err != nil {
+		return nil, err
+	}
+	images := &sonicImages{}
+	if err = json.Unmarshal([]byte(output), images); err != nil {
+		return nil, status.Errorf(codes.Internal, "Invalid image list %q: %v", output, err)
+	}
+	return images, nil
+}
+
+func installError(errType gnoi_os_pb.InstallError_Type, detail string) *gnoi_os_pb.InstallResponse {
+	return &gnoi_os_pb.InstallResponse{Response: &gnoi_os_pb.InstallResponse_InstallError{
+		InstallError: &gnoi_os_pb.InstallError{Type: errType, Detail: detail},
+	}}
+}
+
+func (srv *Server) Install(stream gnoi_os_pb.OS_InstallServer) error {
+	ctx, err := authenticate(srv.config, stream.Context())
+	if err != nil {
+		return err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcOsInstall, Write: true}); err != nil {
+		return err
+	}
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	transfer := req.GetTransferRequest()
+	if transfer == nil {
+		return status.Errorf(codes.InvalidArgument, "First Install request must be a TransferRequest")
+	}
+	if transfer.GetStandbySupervisor() {
+		return status.Errorf(codes.Unimplemented, "Standby supervisor is not supported")
+	}
+	log.V(1).Infof("gNOI: OS Install %v", transfer.GetVersion())
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	if len(md.Get(imageHashMetadataKey)) == 0 && srv.config.RequireImageHash {
+		return status.Errorf(codes.InvalidArgument, "Metadata %s with the SHA-256 of the image is required", imageHashMetadataKey)
+	}
+	if srv.config.ImageDir == "" {
+		return status.Errorf(codes.FailedPrecondition, "No image directory shared with the host is configured")
+	}
+	if !imageInstallMu.TryLock() {
+		return stream.Send(installError(gnoi_os_pb.InstallError_INSTALL_IN_PROGRESS, ""))
+	}
+	defer imageInstallMu.Unlock()
+
+	sc, err := ssc.NewDbusClient()
+	if err != nil {
+		return err
+	}
+	before, err := listSonicImages(sc)
+	if err != nil {
+		return err
+	}
+	if version := transfer.GetVersion(); version != "" && before.has(version) {
+		return stream.Send(&gnoi_os_pb.InstallResponse{Response: &gnoi_os_pb.InstallResponse_Validated{
+			Validated: &gnoi_os_pb.Validated{Version: version},
+		}})
+	}
+
+	tmp, err := ioutil.TempFile(srv.config.ImageDir, "gnoi-os-*.bin")
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err = stream.Send(&gnoi_os_pb.InstallResponse{Response: &gnoi_os_pb.InstallResponse_TransferReady{
+		TransferReady: &gnoi_os_pb.TransferReady{},
+	}}); err != nil {
+		return err
+	}
+
+	maxSize := uint64(DefaultMaxImageSize)
+	if srv.config.MaxImageSize > 0 {
+		maxSize = uint64(srv.config.MaxImageSize)
+	}
+	h := sha256.New()
+	var received, reported uint64
+	for {
+		req, err = stream.Recv()
+		if err == io.EOF {
+			return status.Errorf(codes.InvalidArgument, "Install ended without TransferEnd")
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetTransferEnd() != nil {
+			break
+		}
+		content := req.GetTransferContent()
+		if content == nil {
+			return status.Errorf(codes.InvalidArgument, "Unexpected Install request %v", req)
+		}
+		if received+uint64(len(content)) > maxSize {
+			return stream.Send(installError(gnoi_os_pb.InstallError_TOO_LARGE,
+				"Image exceeds "+strconv.FormatUint(maxSize, 10)+" bytes"))
+		}
+		if _, err = tmp.Write(content); err != nil {
+			return stream.Send(installError(gnoi_os_pb.InstallError_TOO_LARGE, err.Error()))
+		}
+		h.Write(content)
+		received += uint64(len(content))
+		if received-reported >= imageProgressInterval {
+			reported = received
+			if err = stream.Send(&gnoi_os_pb.InstallResponse{Response: &gnoi_os_pb.InstallResponse_TransferProgress{
+				TransferProgress: &gnoi_os_pb.TransferProgress{BytesReceived: received},
+			}}); err != nil {
+				return err
+			}
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	if expected := md.Get(imageHashMetadataKey); len(expected) != 0 {
+		want, err := hex.DecodeString(expected[0])
+		if err != nil || !bytes.Equal(want, h.Sum(nil)) {
+			return stream.Send(installError(gnoi_os_pb.InstallError_INTEGRITY_FAIL,
+				"SHA-256 of the image is "+hex.EncodeToString(h.Sum(nil))))
+		}
+	}
+
+	// The host installer reads the image from the host side of the directory
+	hostImageDir := srv.config.HostImageDir
+	if hostImageDir == "" {
+		hostImageDir = srv.config.ImageDir
+	}
+	hostImage := filepath.Join(hostImageDir, filepath.Base(tmp.Name()))
+	log.V(1).Infof("Installing image %v of %d bytes", hostImage, received)
+	if err = sc.InstallImage(hostImage); err != nil {
+		return stream.Send(installError(gnoi_os_pb.InstallError_INSTALL_RUN_PACKAGE, err.Error()))
+	}
+	after, err := listSonicImages(sc)
+	if err != nil {
+		return err
+	}
+	version := transfer.GetVersion()
+	for _, image := range after.Available {
+		if !before.has(image) {
+			version = image
+		}
+	}
+	return stream.Send(&gnoi_os_pb.InstallResponse{Response: &gnoi_os_pb.InstallResponse_Validated{
+		Validated: &gnoi_os_pb.Validated{Version: version},
+	}})
+}
+
+func (srv *Server) Activate(ctx context.Context, req *gnoi_os_pb.ActivateRequest) (*gnoi_os_pb.ActivateResponse, error) {
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcOsActivate, Write: true}); err != nil {
+		return nil, err
+	}
+	log.V(1).Infof("gNOI: OS Activate %v", req.GetVersion())
+	if req.GetStandbySupervisor() {
+		return nil, status.Errorf(codes.Unimplemented, "Standby supervisor is not supported")
+	}
+	sc, err := ssc.NewDbusClient()
+	if err != nil {
+		return nil, err
+	}
+	images, err := listSonicImages(sc)
+	if err != nil {
+		return nil, err
+	}
+	if !images.has(req.GetVersion()) {
+		return &gnoi_os_pb.ActivateResponse{Response: &gnoi_os_pb.ActivateResponse_ActivateError{
+			ActivateError: &gnoi_os_pb.ActivateError{
+				Type:   gnoi_os_pb.ActivateError_NON_EXISTENT_VERSION,
+				Detail: "Image " + req.GetVersion() + " is not installed",
+			},
+		}}, nil
+	}
+	if err = sc.SetNextBoot(req.GetVersion()); err != nil {
+		return &gnoi_os_pb.ActivateResponse{Response: &gnoi_os_pb.ActivateResponse_ActivateError{
+			ActivateError: &gnoi_os_pb.ActivateError{Type: gnoi_os_pb.ActivateError_UNSPECIFIED, Detail: err.Error()},
+		}}, nil
+	}
+	// The new image is booted right away, as by a cold Reboot, unless requested otherwise
+	if !req.GetNoReboot() {
+		if err = rebooter.schedule(&gnoi_system_pb.RebootRequest{
+			Method:  gnoi_system_pb.RebootMethod_COLD,
+			Message: "Activating " + req.GetVersion(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &gnoi_os_pb.ActivateResponse{Response: &gnoi_os_pb.ActivateResponse_ActivateOk{
+		ActivateOk: &gnoi_os_pb.ActivateOK{},
+	}}, nil
+}
+
+func (srv *Server) Verify(ctx context.Context, req *gnoi_os_pb.VerifyRequest) (*gnoi_os_pb.VerifyResponse, error) {
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcOsVerify}); err != nil {
+		return nil, err
+	}
+	log.V(1).Info("gNOI: OS Verify")
+	sc, err := ssc.NewDbusClient()
+	if err != nil {
+		return nil, err
+	}
+	images, err := listSonicImages(sc)
+	if err != nil {
+		return nil, err
+	}
+	return &gnoi_os_pb.VerifyResponse{Version: images.Current}, nil
+}
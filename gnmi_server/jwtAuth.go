@@ -3,6 +3,7 @@ package gnmi
 import (
 	"github.com/sonic-net/sonic-gnmi/common_utils"
 	"crypto/rand"
+	"encoding/hex"
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/golang/glog"
 	"golang.org/x/net/context"
@@ -16,7 +17,6 @@ import (
 var (
 	JwtRefreshInt    time.Duration
 	JwtValidInt      time.Duration
-	hmacSampleSecret = make([]byte, 16)
 )
 
 type Credentials struct {
@@ -31,6 +31,12 @@ type Claims struct {
 }
 
 func generateJWT(username string, roles []string, expire_dt time.Time) string {
+	jti, err := newJwtId()
+	if err != nil {
+		glog.Errorf("Failed to generate JWT id: %v", err)
+		return ""
+	}
+	now := time.Now()
 	// Create a new token object, specifying signing method and the claims
 	// you would like it to contain.
 	claims := &Claims{
@@ -39,17 +45,39 @@ func generateJWT(username string, roles []string, expire_dt time.Time) string {
 		StandardClaims: jwt.StandardClaims{
 			// In JWT, the expiry time is expressed as unix milliseconds
 			ExpiresAt: expire_dt.Unix(),
+			IssuedAt:  now.Unix(),
+			// The jti claim identifies the session for revocation
+			Id: jti,
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign and get the complete encoded token as a string using the secret
-	tokenString, _ := token.SignedString(hmacSampleSecret)
+	// Sign and get the complete encoded token as a string using the current key
+	tokenString, err := jwtKeys.sign(claims)
+	if err != nil {
+		glog.Errorf("Failed to sign JWT token: %v", err)
+		return ""
+	}
+	if err = jwtSessions.add(&jwtSession{Jti: jti, Username: username, Roles: roles, IssuedAt: now, ExpiresAt: expire_dt}); err != nil {
+		glog.Errorf("Failed to record JWT session: %v", err)
+		return ""
+	}
 
 	return tokenString
 }
+
+func newJwtId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// GenerateJwtSecretKey rotates the JWT signing key. Tokens signed with the
+// previous key stay valid until they expire.
 func GenerateJwtSecretKey() {
-	rand.Read(hmacSampleSecret)
+	if err := jwtKeys.Rotate(); err != nil {
+		glog.Errorf("Failed to rotate JWT signing key: %v", err)
+	}
 }
 
 func tokenResp(username string, roles []string) *spb.JwtToken {
@@ -73,15 +101,22 @@ func JwtAuthenAndAuthor(ctx context.Context) (*spb.JwtToken, context.Context, er
 	}
 
 	claims := &Claims{}
-	tkn, err := jwt.ParseWithClaims(token.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
-		return hmacSampleSecret, nil
-	})
+	tkn, err := jwt.ParseWithClaims(token.AccessToken, claims, jwtKeys.keyFunc)
 	if err != nil {
 		return &token, ctx, status.Errorf(codes.Unauthenticated, err.Error())
 	}
 	if !tkn.Valid {
 		return &token, ctx, status.Errorf(codes.Unauthenticated, "Invalid JWT Token")
 	}
+	if claims.Id != "" {
+		revoked, err := jwtSessions.isRevoked(claims.Id)
+		if err != nil {
+			return &token, ctx, status.Errorf(codes.Unauthenticated, "JWT Token revocation cannot be checked")
+		}
+		if revoked {
+			return &token, ctx, status.Errorf(codes.Unauthenticated, "JWT Token revoked")
+		}
+	}
 	if err := PopulateAuthStruct(claims.Username, &rc.Auth, claims.Roles); err != nil {
 		glog.Infof("[%s] Failed to retrieve authentication information; %v", rc.ID, err)
 		return &token, ctx, status.Errorf(codes.Unauthenticated, "")
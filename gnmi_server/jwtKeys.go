@@ -0,0 +1,306 @@
+package gnmi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	log "github.com/golang/glog"
+)
+
+// Supported JWT signing algorithms.
+const (
+	JwtAlgHS256 = "HS256"
+	JwtAlgRS256 = "RS256"
+	JwtAlgES256 = "ES256"
+)
+
+// jwtKey is a JWT signing key as persisted in the key file.
+type jwtKey struct {
+	Kid     string    `json:"kid"`
+	Alg     string    `json:"alg"`
+	Created time.Time `json:"created"`
+	// When the key stopped signing tokens, zero for the signing key
+	Retired time.Time `json:"retired,omitempty"`
+	// Base64 secret for HS256, PKCS8 PEM private key otherwise
+	Key []byte `json:"key"`
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type jwtKeyFile struct {
+	Keys []*jwtKey `json:"keys"`
+}
+
+// JwtKeyStore holds the keys signing and verifying JWT tokens. The newest key
+// signs new tokens, identified by their "kid" header. Keys replaced by a
+// rotation keep verifying the tokens they signed until these expire. The keys
+// are persisted in a file, if any, so that tokens survive server restarts.
+type JwtKeyStore struct {
+	mu   sync.RWMutex
+	alg  string
+	file string
+	keys []*jwtKey // oldest first, the last one is the signing key
+}
+
+// Keys used to sign and verify the JWT tokens, in memory with a HS256 key
+// unless replaced by SetJwtKeyStore.
+var jwtKeys, _ = NewJwtKeyStore(JwtAlgHS256, "")
+
+// SetJwtKeyStore sets the key store used to sign and verify JWT tokens.
+func SetJwtKeyStore(ks *JwtKeyStore) {
+	jwtKeys = ks
+}
+
+// NewJwtKeyStore returns a key store signing with alg. Keys are loaded from
+// and saved to fileName, unless empty. A new signing key is generated if
+// there is none yet or the current one uses another algorithm.
+func NewJwtKeyStore(alg string, fileName string) (*JwtKeyStore, error) {
+	switch alg {
+	case JwtAlgHS256, JwtAlgRS256, JwtAlgES256:
+	default:
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", alg)
+	}
+	ks := &JwtKeyStore{alg: alg, file: fileName}
+	if fileName != "" {
+		if err := ks.load(); err != nil {
+			return nil, err
+		}
+	}
+	if current := ks.current(); current == nil || current.Alg != alg {
+		if err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func (ks *JwtKeyStore) load() error {
+	data, err := ioutil.ReadFile(ks.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read JWT key file %v: %v", ks.file, err)
+	}
+	kf := &jwtKeyFile{}
+	if err = json.Unmarshal(data, kf); err != nil {
+		return fmt.Errorf("parse JWT key file %v: %v", ks.file, err)
+	}
+	for _, k := range kf.Keys {
+		if err = k.parse(); err != nil {
+			return fmt.Errorf("JWT key %v in %v: %v", k.Kid, ks.file, err)
+		}
+	}
+	ks.keys = kf.Keys
+	log.V(1).Infof("Loaded %d JWT keys from %v", len(ks.keys), ks.file)
+	return nil
+}
+
+// save writes the keys to the key file, replacing it atomically.
+func (ks *JwtKeyStore) save() error {
+	if ks.file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(&jwtKeyFile{Keys: ks.keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(ks.file), filepath.Base(ks.file)+".tmp")
+	if err != nil {
+		return fmt.Errorf("save JWT key file %v: %v", ks.file, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Chmod(0600)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), ks.file)
+	}
+	if err != nil {
+		return fmt.Errorf("save JWT key file %v: %v", ks.file, err)
+	}
+	return nil
+}
+
+func (ks *JwtKeyStore) current() *jwtKey {
+	if len(ks.keys) == 0 {
+		return nil
+	}
+	return ks.keys[len(ks.keys)-1]
+}
+
+// Rotate generates a new signing key. The previous keys are kept to verify
+// the tokens they signed until these expire, after JwtValidInt.
+func (ks *JwtKeyStore) Rotate() error {
+	k, err := newJwtKey(ks.alg)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := time.Now()
+	if current := ks.current(); current != nil {
+		current.Retired = now
+	}
+	var keys []*jwtKey
+	for _, old := range ks.keys {
+		// Tokens signed before retirement are valid for JwtValidInt at most
+		if now.Sub(old.Retired) <= JwtValidInt {
+			keys = append(keys, old)
+		} else {
+			log.V(2).Infof("Removed expired JWT key %v", old.Kid)
+		}
+	}
+	ks.keys = append(keys, k)
+	log.V(1).Infof("New %v JWT signing key %v", k.Alg, k.Kid)
+	return ks.save()
+}
+
+// RotateIfOlder rotates the signing key if it was created more than age ago.
+func (ks *JwtKeyStore) RotateIfOlder(age time.Duration) error {
+	ks.mu.RLock()
+	current := ks.current()
+	ks.mu.RUnlock()
+	if current != nil && time.Since(current.Created) < age {
+		return nil
+	}
+	return ks.Rotate()
+}
+
+// RotateJwtKeys rotates the signing key of the key store in use whenever it
+// gets older than interval. It never returns.
+func RotateJwtKeys(interval time.Duration) {
+	for {
+		ks := jwtKeys
+		if err := ks.RotateIfOlder(interval); err != nil {
+			log.Errorf("Failed to rotate JWT signing key: %v", err)
+		}
+		ks.mu.RLock()
+		next := time.Until(ks.current().Created.Add(interval))
+		ks.mu.RUnlock()
+		if next <= 0 {
+			// Rotation failed, retry later
+			next = time.Minute
+		}
+		time.Sleep(next)
+	}
+}
+
+// sign returns the signed token of claims.
+func (ks *JwtKeyStore) sign(claims jwt.Claims) (string, error) {
+	ks.mu.RLock()
+	k := ks.current()
+	ks.mu.RUnlock()
+	if k == nil {
+		return "", fmt.Errorf("no JWT signing key")
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(k.Alg), claims)
+	token.Header["kid"] = k.Kid
+	return token.SignedString(k.signKey)
+}
+
+// keyFunc returns the key verifying token, selected by its "kid" header. The
+// token must use the algorithm of the key.
+func (ks *JwtKeyStore) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	for _, k := range ks.keys {
+		if k.Kid != kid {
+			continue
+		}
+		if token.Method.Alg() != k.Alg {
+			return nil, fmt.Errorf("unexpected signing method %v for key %v", token.Method.Alg(), kid)
+		}
+		return k.verifyKey, nil
+	}
+	return nil, fmt.Errorf("unknown JWT key %q", kid)
+}
+
+func newJwtKey(alg string) (*jwtKey, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	k := &jwtKey{Kid: hex.EncodeToString(id), Alg: alg, Created: time.Now()}
+
+	var priv interface{}
+	var err error
+	switch alg {
+	case JwtAlgHS256:
+		k.Key = make([]byte, 32)
+		_, err = rand.Read(k.Key)
+	case JwtAlgRS256:
+		priv, err = rsa.GenerateKey(rand.Reader, 2048)
+	case JwtAlgES256:
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		err = fmt.Errorf("unsupported JWT signing algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if priv != nil {
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		k.Key = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	if err = k.parse(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// parse sets the signing and verification keys from the persisted key.
+func (k *jwtKey) parse() error {
+	if k.Alg == JwtAlgHS256 {
+		if len(k.Key) == 0 {
+			return fmt.Errorf("empty secret")
+		}
+		k.signKey, k.verifyKey = k.Key, k.Key
+		return nil
+	}
+	block, _ := pem.Decode(k.Key)
+	if block == nil {
+		return fmt.Errorf("no PEM private key")
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return err
+	}
+	switch key := priv.(type) {
+	case *rsa.PrivateKey:
+		if k.Alg != JwtAlgRS256 {
+			return fmt.Errorf("RSA key for %v", k.Alg)
+		}
+		k.signKey, k.verifyKey = key, &key.PublicKey
+	case *ecdsa.PrivateKey:
+		if k.Alg != JwtAlgES256 {
+			return fmt.Errorf("ECDSA key for %v", k.Alg)
+		}
+		k.signKey, k.verifyKey = key, &key.PublicKey
+	default:
+		return fmt.Errorf("unsupported private key type %T", priv)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package gnmi
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	log "github.com/golang/glog"
+	"github.com/sonic-net/sonic-gnmi/common_utils"
+	spb_jwt "github.com/sonic-net/sonic-gnmi/proto/gnoi/jwt"
+	sdcfg "github.com/sonic-net/sonic-gnmi/sonic_db_config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// STATE_DB table of the issued JWT tokens, keyed by their jti claim. Entries
+// expire with their token, so that the revoked ones form the revocation list.
+const jwtSessionTable = "JWT_SESSION"
+
+// Role of the users managing the JWT sessions of all users, the others only
+// manage their own.
+const jwtSessionAdminRole = "admin"
+
+// jwtSession is an issued JWT token.
+type jwtSession struct {
+	Jti       string
+	Username  string
+	Roles     []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Revoked   bool
+}
+
+func (s *jwtSession) toProto() *spb_jwt.Session {
+	return &spb_jwt.Session{
+		Jti:       s.Jti,
+		Username:  s.Username,
+		Roles:     s.Roles,
+		IssuedAt:  s.IssuedAt.Unix(),
+		ExpiresAt: s.ExpiresAt.Unix(),
+		Revoked:   s.Revoked,
+	}
+}
+
+// jwtSessionStore tracks the unexpired JWT sessions in memory, persisted in
+// STATE_DB so that revocations survive server restarts. The sessions are
+// loaded from STATE_DB on first use. Until they are, JWT tokens can neither
+// be issued nor accepted, as their revocation cannot be checked.
+type jwtSessionStore struct {
+	mu        sync.Mutex
+	loaded    bool
+	sessions  map[string]*jwtSession
+	client    *redis.Client
+	separator string
+}
+
+var jwtSessions = &jwtSessionStore{}
+
+// init loads the sessions from STATE_DB if not done yet, a failed load is
+// retried by the next call. ss.mu must be held.
+func (ss *jwtSessionStore) init() error {
+	if ss.loaded {
+		return nil
+	}
+	if err := ss.load(); err != nil {
+		log.Errorf("Failed to load JWT sessions from STATE_DB: %v", err)
+		return status.Errorf(codes.Unavailable, "JWT sessions are not available")
+	}
+	ss.loaded = true
+	return nil
+}
+
+func (ss *jwtSessionStore) load() error {
+	ns, _ := sdcfg.GetDbDefaultNamespace()
+	addr, err := sdcfg.GetDbTcpAddr("STATE_DB", ns)
+	if err != nil {
+		return err
+	}
+	db, err := sdcfg.GetDbId("STATE_DB", ns)
+	if err != nil {
+		return err
+	}
+	separator, err := sdcfg.GetDbSeparator("STATE_DB", ns)
+	if err != nil {
+		return err
+	}
+	client := redis.NewClient(&redis.Options{
+		Network:     "tcp",
+		Addr:        addr,
+		Password:    "",
+		DB:          db,
+		DialTimeout: 0,
+	})
+
+	keys, err := client.Keys(jwtSessionTable + separator + "*").Result()
+	if err != nil {
+		client.Close()
+		return err
+	}
+	sessions := map[string]*jwtSession{}
+	now := time.Now()
+	for _, key := range keys {
+		fv, err := client.HGetAll(key).Result()
+		if err != nil {
+			client.Close()
+			return err
+		}
+		s := &jwtSession{
+			Jti:       strings.TrimPrefix(key, jwtSessionTable+separator),
+			Username:  fv["username"],
+			Roles:     splitPolicyList(fv["roles"]),
+			IssuedAt:  parseUnixField(fv["issued_at"]),
+			ExpiresAt: parseUnixField(fv["expires_at"]),
+			Revoked:   fv["revoked"] == "true",
+		}
+		if now.Before(s.ExpiresAt) {
+			sessions[s.Jti] = s
+		}
+	}
+	ss.client, ss.separator, ss.sessions = client, separator, sessions
+	log.V(1).Infof("Loaded %d JWT sessions from STATE_DB", len(ss.sessions))
+	return nil
+}
+
+func parseUnixField(value string) time.Time {
+	sec, _ := strconv.ParseInt(value, 10, 64)
+	return time.Unix(sec, 0)
+}
+
+// save writes the session to STATE_DB.
+func (ss *jwtSessionStore) save(s *jwtSession) {
+	if ss.client == nil {
+		return
+	}
+	key := jwtSessionTable + ss.separator + s.Jti
+	fields := map[string]interface{}{
+		"username":   s.Username,
+		"roles":      strings.Join(s.Roles, ","),
+		"issued_at":  strconv.FormatInt(s.IssuedAt.Unix(), 10),
+		"expires_at": strconv.FormatInt(s.ExpiresAt.Unix(), 10),
+		"revoked":    strconv.FormatBool(s.Revoked),
+	}
+	if err := ss.client.HMSet(key, fields).Err(); err != nil {
+		log.V(1).Infof("Failed to save JWT session %v: %v", s.Jti, err)
+		return
+	}
+	if err := ss.client.ExpireAt(key, s.ExpiresAt).Err(); err != nil {
+		log.V(1).Infof("Failed to set expiry of JWT session %v: %v", s.Jti, err)
+	}
+}
+
+// prune removes the expired sessions, STATE_DB expires them by itself.
+func (ss *jwtSessionStore) prune(now time.Time) {
+	for jti, s := range ss.sessions {
+		if !now.Before(s.ExpiresAt) {
+			delete(ss.sessions, jti)
+		}
+	}
+}
+
+// add records a newly issued session.
+func (ss *jwtSessionStore) add(s *jwtSession) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.init(); err != nil {
+		return err
+	}
+	ss.prune(time.Now())
+	ss.sessions[s.Jti] = s
+	ss.save(s)
+	return nil
+}
+
+// user returns the user of the session jti, "" if unknown.
+func (ss *jwtSessionStore) user(jti string) (string, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.init(); err != nil {
+		return "", err
+	}
+	if s, ok := ss.sessions[jti]; ok {
+		return s.Username, nil
+	}
+	return "", nil
+}
+
+// isRevoked reports whether the session jti was revoked.
+func (ss *jwtSessionStore) isRevoked(jti string) (bool, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.init(); err != nil {
+		return false, err
+	}
+	s, ok := ss.sessions[jti]
+	return ok && s.Revoked, nil
+}
+
+// revoke revokes the session jti if not empty, and all the sessions of
+// username if not empty. It returns the sessions newly revoked.
+func (ss *jwtSessionStore) revoke(jti string, username string) ([]*jwtSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.init(); err != nil {
+		return nil, err
+	}
+	ss.prune(time.Now())
+	var revoked []*jwtSession
+	for _, s := range ss.sessions {
+		if s.Revoked || (s.Jti != jti && (username == "" || s.Username != username)) {
+			continue
+		}
+		s.Revoked = true
+		ss.save(s)
+		log.V(1).Infof("Revoked JWT session %v of user %v", s.Jti, s.Username)
+		revoked = append(revoked, s)
+	}
+	sortJwtSessions(revoked)
+	return revoked, nil
+}
+
+// list returns the unexpired sessions, of username only if not empty.
+func (ss *jwtSessionStore) list(username string) ([]*jwtSession, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if err := ss.init(); err != nil {
+		return nil, err
+	}
+	ss.prune(time.Now())
+	var sessions []*jwtSession
+	for _, s := range ss.sessions {
+		if username == "" || s.Username == username {
+			sessions = append(sessions, s)
+		}
+	}
+	sortJwtSessions(sessions)
+	return sessions, nil
+}
+
+// jwtSessionOwner returns the user whose JWT sessions the caller of ctx
+// manages, "" for the sessions of all users when authentication is disabled
+// or the caller has the admin role.
+func jwtSessionOwner(ctx context.Context) (string, error) {
+	rc, _ := common_utils.GetContext(ctx)
+	if !rc.Auth.AuthEnabled || containsString(rc.Auth.Roles, jwtSessionAdminRole) {
+		return "", nil
+	}
+	if rc.Auth.User == "" {
+		return "", status.Errorf(codes.PermissionDenied, "JWT sessions can only be managed by their user")
+	}
+	return rc.Auth.User, nil
+}
+
+func sortJwtSessions(sessions []*jwtSession) {
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].IssuedAt.Equal(sessions[j].IssuedAt) {
+			return sessions[i].IssuedAt.Before(sessions[j].IssuedAt)
+		}
+		return sessions[i].Jti < sessions[j].Jti
+	})
+}
+
+func jwtSessionsToProto(sessions []*jwtSession) []*spb_jwt.Session {
+	var pbs []*spb_jwt.Session
+	for _, s := range sessions {
+		pbs = append(pbs, s.toProto())
+	}
+	return pbs
+}
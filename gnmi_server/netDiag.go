@@ -0,0 +1,438 @@
+package gnmi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/golang/glog"
+	gnoi_system_pb "github.com/openconfig/gnoi/system"
+	"github.com/openconfig/gnoi/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// gRPC metadata keys selecting the network namespace and the VRF in which
+// Ping and Traceroute run, as their requests have no such field.
+const (
+	netnsMetadataKey = "network-namespace"
+	vrfMetadataKey   = "vrf"
+)
+
+// Number of packets sent by Ping requests without count.
+const defaultPingCount = 5
+
+// CommandRunner runs the commands of the gNOI network diagnostics.
+type CommandRunner interface {
+	// Run runs the command name with args and calls fn with each line of its
+	// standard output, until fn fails or ctx is done.
+	Run(ctx context.Context, name string, args []string, fn func(line string) error) error
+}
+
+type execCommandRunner struct{}
+
+func (execCommandRunner) Run(ctx context.Context, name string, args []string, fn func(line string) error) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if err = fn(scanner.Text()); err != nil {
+			cmd.Process.Kill()
+			cmd.Wait()
+			return err
+		}
+	}
+	if err = cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Runner of the Ping and Traceroute commands, replaced by tests.
+var diagRunner CommandRunner = execCommandRunner{}
+
+var netInstanceName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// diagCommand returns the command running name with args in the network
+// namespace and the VRF selected by the metadata of ctx, if any.
+func diagCommand(ctx context.Context, name string, args []string) (string, []string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var prefix []string
+	if netns := md.Get(netnsMetadataKey); len(netns) != 0 {
+		if !netInstanceName.MatchString(netns[0]) {
+			return "", nil, status.Errorf(codes.InvalidArgument, "Invalid network namespace %q", netns[0])
+		}
+		prefix = append(prefix, "ip", "netns", "exec", netns[0])
+	}
+	if vrf := md.Get(vrfMetadataKey); len(vrf) != 0 {
+		if !netInstanceName.MatchString(vrf[0]) {
+			return "", nil, status.Errorf(codes.InvalidArgument, "Invalid VRF %q", vrf[0])
+		}
+		prefix = append(prefix, "ip", "vrf", "exec", vrf[0])
+	}
+	if len(prefix) == 0 {
+		return name, args, nil
+	}
+	return prefix[0], append(append(prefix[1:], name), args...), nil
+}
+
+// checkDestination rejects an empty destination, or one that ping and
+// traceroute would take for an option.
+func checkDestination(dest string) error {
+	if dest == "" {
+		return status.Errorf(codes.InvalidArgument, "Destination is required")
+	}
+	if strings.HasPrefix(dest, "-") {
+		return status.Errorf(codes.InvalidArgument, "Invalid destination %q", dest)
+	}
+	return nil
+}
+
+// seconds formats the nanoseconds ns as seconds for command options.
+func seconds(ns int64) string {
+	return strconv.FormatFloat(time.Duration(ns).Seconds(), 'f', -1, 64)
+}
+
+// pingAuthzRequest returns the authorization request of a ping. Flooding
+// and pinging without count need write access, as they load the network.
+func pingAuthzRequest(req *gnoi_system_pb.PingRequest) *AuthzRequest {
+	return &AuthzRequest{Rpc: rpcSystemPing, Write: req.GetInterval() == -1 || req.GetCount() == -1}
+}
+
+func pingArgs(req *gnoi_system_pb.PingRequest) ([]string, error) {
+	if err := checkDestination(req.GetDestination()); err != nil {
+		return nil, err
+	}
+	// A count of -1 pings until the RPC is cancelled
+	count := req.GetCount()
+	if count < -1 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid count %d", count)
+	}
+	if count == 0 {
+		count = defaultPingCount
+	}
+	var args []string
+	if count > 0 {
+		args = append(args, "-c", strconv.Itoa(int(count)))
+	}
+	switch req.GetL3Protocol() {
+	case types.L3Protocol_IPV4:
+		args = append(args, "-4")
+	case types.L3Protocol_IPV6:
+		args = append(args, "-6")
+	}
+	// An interval of -1 floods the destination
+	if req.GetInterval() < -1 || req.GetWait() < 0 || req.GetSize() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid negative interval, wait or size")
+	}
+	if req.GetInterval() == -1 {
+		args = append(args, "-f")
+	} else if req.GetInterval() > 0 {
+		args = append(args, "-i", seconds(req.GetInterval()))
+	}
+	if req.GetWait() > 0 {
+		args = append(args, "-W", seconds(req.GetWait()))
+	}
+	if req.GetSize() > 0 {
+		args = append(args, "-s", strconv.Itoa(int(req.GetSize())))
+	}
+	if req.GetDoNotFragment() {
+		args = append(args, "-M", "do")
+	}
+	if req.GetDoNotResolve() {
+		args = append(args, "-n")
+	}
+	if req.GetSource() != "" {
+		args = append(args, "-I", req.GetSource())
+	}
+	return append(args, req.GetDestination()), nil
+}
+
+var (
+	// 64 bytes from dns.google (8.8.8.8): icmp_seq=1 ttl=117 time=1.23 ms
+	pingReplyRe = regexp.MustCompile(`^(\d+) bytes from (?:\S+ \()?([^\s()]+?)\)?: icmp_seq=(\d+) ttl=(\d+) time=([\d.]+) ms`)
+	// 3 packets transmitted, 3 received, 0% packet loss, time 2003ms
+	pingStatsRe = regexp.MustCompile(`^(\d+) packets transmitted, (\d+) received.*?(?:, time (\d+)ms)?$`)
+	// rtt min/avg/max/mdev = 1.100/1.200/1.300/0.050 ms
+	pingRttRe = regexp.MustCompile(`^(?:rtt|round-trip) min/avg/max/(?:mdev|stddev) = ([\d.]+)/([\d.]+)/([\d.]+)/([\d.]+) ms`)
+)
+
+func msToNs(ms string) int64 {
+	f, _ := strconv.ParseFloat(ms, 64)
+	return int64(f * float64(time.Millisecond))
+}
+
+func atoi32(s string) int32 {
+	i, _ := strconv.Atoi(s)
+	return int32(i)
+}
+
+// pingParser turns the output lines of ping into PingResponses, one per reply
+// and the summary last.
+type pingParser struct {
+	destination string
+	summary     *gnoi_system_pb.PingResponse
+}
+
+// parse returns the response of line, if any. The summary is returned once
+// complete, by the rtt line or by the end of output.
+func (p *pingParser) parse(line string) *gnoi_system_pb.PingResponse {
+	line = strings.TrimSpace(line)
+	if m := pingReplyRe.FindStringSubmatch(line); m != nil {
+		return &gnoi_system_pb.PingResponse{
+			Source:   m[2],
+			Time:     msToNs(m[5]),
+			Bytes:    atoi32(m[1]),
+			Sequence: atoi32(m[3]),
+			Ttl:      atoi32(m[4]),
+		}
+	}
+	if m := pingStatsRe.FindStringSubmatch(line); m != nil {
+		p.summary = &gnoi_system_pb.PingResponse{
+			Source:   p.destination,
+			Sent:     atoi32(m[1]),
+			Received: atoi32(m[2]),
+			Time:     msToNs(m[3]),
+		}
+		return nil
+	}
+	if m := pingRttRe.FindStringSubmatch(line); m != nil && p.summary != nil {
+		resp := p.summary
+		resp.MinTime = msToNs(m[1])
+		resp.AvgTime = msToNs(m[2])
+		resp.MaxTime = msToNs(m[3])
+		resp.StdDev = msToNs(m[4])
+		p.summary = nil
+		return resp
+	}
+	return nil
+}
+
+func (srv *Server) Ping(req *gnoi_system_pb.PingRequest, rs gnoi_system_pb.System_PingServer) error {
+	ctx := rs.Context()
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return err
+	}
+	if err = authorize(srv.config, ctx, pingAuthzRequest(req)); err != nil {
+		return err
+	}
+	log.V(1).Info("gNOI: Ping")
+	log.V(1).Info("Request: ", req)
+	args, err := pingArgs(req)
+	if err != nil {
+		return err
+	}
+	name, args, err := diagCommand(ctx, "ping", args)
+	if err != nil {
+		return err
+	}
+	p := &pingParser{destination: req.GetDestination()}
+	summarized := false
+	err = diagRunner.Run(ctx, name, args, func(line string) error {
+		resp := p.parse(line)
+		if resp == nil {
+			return nil
+		}
+		summarized = resp.Sent != 0
+		return rs.Send(resp)
+	})
+	// Without replies there is no rtt line, and ping exits with an error
+	// after the statistics, as it does when some replies are missing.
+	if p.summary != nil {
+		summarized = true
+		if err = rs.Send(p.summary); err != nil {
+			return err
+		}
+	}
+	if err != nil && !summarized {
+		log.V(1).Infof("Ping %v failed: %v", req.GetDestination(), err)
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Ping failed: %v", err)
+	}
+	return nil
+}
+
+func tracerouteArgs(req *gnoi_system_pb.TracerouteRequest) ([]string, error) {
+	if err := checkDestination(req.GetDestination()); err != nil {
+		return nil, err
+	}
+	var args []string
+	switch req.GetL3Protocol() {
+	case types.L3Protocol_IPV4:
+		args = append(args, "-4")
+	case types.L3Protocol_IPV6:
+		args = append(args, "-6")
+	}
+	switch req.GetL4Protocol() {
+	case gnoi_system_pb.TracerouteRequest_ICMP:
+		args = append(args, "-I")
+	case gnoi_system_pb.TracerouteRequest_TCP:
+		args = append(args, "-T")
+	case gnoi_system_pb.TracerouteRequest_UDP:
+		args = append(args, "-U")
+	}
+	if req.GetMaxTtl() < 0 || req.GetWait() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid negative max_ttl or wait")
+	}
+	if req.GetInitialTtl() > 0 {
+		args = append(args, "-f", strconv.Itoa(int(req.GetInitialTtl())))
+	}
+	if req.GetMaxTtl() > 0 {
+		args = append(args, "-m", strconv.Itoa(int(req.GetMaxTtl())))
+	}
+	if req.GetWait() > 0 {
+		args = append(args, "-w", seconds(req.GetWait()))
+	}
+	if req.GetDoNotFragment() {
+		args = append(args, "-F")
+	}
+	if req.GetDoNotResolve() {
+		args = append(args, "-n")
+	}
+	if req.GetSource() != "" {
+		args = append(args, "-s", req.GetSource())
+	}
+	return append(args, req.GetDestination()), nil
+}
+
+// traceroute to dns.google (8.8.8.8), 30 hops max, 60 byte packets
+var tracerouteHeaderRe = regexp.MustCompile(`^traceroute to (\S+) \(([^)]+)\), (\d+) hops max, (\d+) byte packets`)
+
+// States of the traceroute probe annotations.
+var tracerouteStates = map[string]gnoi_system_pb.TracerouteResponse_State{
+	"!H": gnoi_system_pb.TracerouteResponse_HOST_UNREACHABLE,
+	"!N": gnoi_system_pb.TracerouteResponse_NETWORK_UNREACHABLE,
+	"!P": gnoi_system_pb.TracerouteResponse_PROTOCOL_UNREACHABLE,
+	"!S": gnoi_system_pb.TracerouteResponse_SOURCE_ROUTE_FAILED,
+	"!F": gnoi_system_pb.TracerouteResponse_FRAGMENTATION_NEEDED,
+	"!X": gnoi_system_pb.TracerouteResponse_PROHIBITED,
+	"!V": gnoi_system_pb.TracerouteResponse_PRECEDENCE_VIOLATION,
+	"!C": gnoi_system_pb.TracerouteResponse_PRECEDENCE_CUTOFF,
+}
+
+// parseTracerouteLine returns the responses of an output line of traceroute,
+// one per probe of hop lines such as:
+//
+//	1  gateway (192.168.1.1)  0.345 ms  0.300 ms !H  *
+func parseTracerouteLine(line string) []*gnoi_system_pb.TracerouteResponse {
+	if m := tracerouteHeaderRe.FindStringSubmatch(line); m != nil {
+		return []*gnoi_system_pb.TracerouteResponse{{
+			DestinationName:    m[1],
+			DestinationAddress: m[2],
+			Hops:               atoi32(m[3]),
+			PacketSize:         atoi32(m[4]),
+		}}
+	}
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil
+	}
+	hop, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil
+	}
+	var resps []*gnoi_system_pb.TracerouteResponse
+	var name, address string
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "*":
+			resps = append(resps, &gnoi_system_pb.TracerouteResponse{
+				Hop:   int32(hop),
+				State: gnoi_system_pb.TracerouteResponse_NONE,
+			})
+		case i+1 < len(fields) && fields[i+1] == "ms":
+			resp := &gnoi_system_pb.TracerouteResponse{
+				Hop:     int32(hop),
+				Address: address,
+				Name:    name,
+				Rtt:     msToNs(field),
+			}
+			i++
+			if i+1 < len(fields) && strings.HasPrefix(fields[i+1], "!") {
+				i++
+				resp.State = tracerouteState(fields[i], resp)
+			}
+			resps = append(resps, resp)
+		case i+1 < len(fields) && strings.HasPrefix(fields[i+1], "("):
+			name = field
+			address = strings.Trim(fields[i+1], "()")
+			i++
+		default:
+			name, address = "", field
+		}
+	}
+	return resps
+}
+
+func tracerouteState(annotation string, resp *gnoi_system_pb.TracerouteResponse) gnoi_system_pb.TracerouteResponse_State {
+	if len(annotation) >= 2 {
+		if state, ok := tracerouteStates[annotation[:2]]; ok {
+			return state
+		}
+	}
+	// !<num> reports the ICMP code
+	if code, err := strconv.Atoi(annotation[1:]); err == nil {
+		resp.IcmpCode = int32(code)
+		return gnoi_system_pb.TracerouteResponse_ICMP
+	}
+	return gnoi_system_pb.TracerouteResponse_UNKNOWN
+}
+
+func (srv *Server) Traceroute(req *gnoi_system_pb.TracerouteRequest, rs gnoi_system_pb.System_TracerouteServer) error {
+	ctx := rs.Context()
+	ctx, err := authenticate(srv.config, ctx)
+	if err != nil {
+		return err
+	}
+	if err = authorize(srv.config, ctx, &AuthzRequest{Rpc: rpcSystemTraceroute}); err != nil {
+		return err
+	}
+	log.V(1).Info("gNOI: Traceroute")
+	log.V(1).Info("Request: ", req)
+	args, err := tracerouteArgs(req)
+	if err != nil {
+		return err
+	}
+	name, args, err := diagCommand(ctx, "traceroute", args)
+	if err != nil {
+		return err
+	}
+	err = diagRunner.Run(ctx, name, args, func(line string) error {
+		for _, resp := range parseTracerouteLine(line) {
+			if err := rs.Send(resp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.V(1).Infof("Traceroute %v failed: %v", req.GetDestination(), err)
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Traceroute failed: %v", err)
+	}
+	return nil
+}
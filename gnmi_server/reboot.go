@@ -0,0 +1,146 @@
+package gnmi
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/golang/glog"
+	gnoi_system_pb "github.com/openconfig/gnoi/system"
+	"github.com/sonic-net/sonic-gnmi/common_utils"
+	ssc "github.com/sonic-net/sonic-gnmi/sonic_service_client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reboot methods handled by the gnoi_reboot host service, with the SONiC
+// reboot they trigger. gNOI has no fast reboot method, NSF stands for it.
+var supportedRebootMethods = map[gnoi_system_pb.RebootMethod]string{
+	gnoi_system_pb.RebootMethod_COLD:      "reboot",
+	gnoi_system_pb.RebootMethod_POWERDOWN: "shutdown",
+	gnoi_system_pb.RebootMethod_WARM:      "warm-reboot",
+	gnoi_system_pb.RebootMethod_NSF:       "fast-reboot",
+}
+
+// rebootScheduler issues the reboots requested through gNOI, at once or
+// after their delay. A single reboot can be pending at a time.
+type rebootScheduler struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	active bool // a reboot is pending or being issued
+	issued bool // the pending reboot is being issued
+	when   time.Time
+	reason string
+	count  uint32
+}
+
+var rebooter = &rebootScheduler{}
+
+// issueReboot reboots the host with method. The UNKNOWN method keeps the
+// legacy behavior of reloading the configuration.
+func issueReboot(method gnoi_system_pb.RebootMethod, message string) error {
+	if method == gnoi_system_pb.RebootMethod_UNKNOWN {
+		fileName := common_utils.GNMI_WORK_PATH + "/config_db.json.tmp"
+		config_db_json, err := ioutil.ReadFile(fileName)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return RebootSystem(string(config_db_json))
+	}
+	log.V(2).Infof("Rebooting with %s...", supportedRebootMethods[method])
+	sc, err := ssc.NewDbusClient()
+	if err != nil {
+		return err
+	}
+	return sc.Reboot(int32(method), message)
+}
+
+// schedule issues the reboot requested by req after its delay, or before
+// returning if there is no delay.
+func (rs *rebootScheduler) schedule(req *gnoi_system_pb.RebootRequest) error {
+	method := req.GetMethod()
+	if _, ok := supportedRebootMethods[method]; !ok && method != gnoi_system_pb.RebootMethod_UNKNOWN {
+		return status.Errorf(codes.Unimplemented, "Reboot method %v is not supported", method)
+	}
+	if len(req.GetSubcomponents()) != 0 {
+		return status.Errorf(codes.Unimplemented, "Reboot of subcomponents is not supported")
+	}
+	if req.GetDelay() > math.MaxInt64 {
+		return status.Errorf(codes.InvalidArgument, "Reboot delay %d ns is out of range", req.GetDelay())
+	}
+	delay := time.Duration(req.GetDelay())
+
+	rs.mu.Lock()
+	if rs.active {
+		rs.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "Reboot is already pending")
+	}
+	rs.active = true
+	rs.issued = false
+	rs.when = time.Now().Add(delay)
+	rs.reason = req.GetMessage()
+	if delay > 0 {
+		log.V(1).Infof("Reboot with method %v scheduled at %v", method, rs.when)
+		rs.timer = time.AfterFunc(delay, func() {
+			if err := rs.issue(method, req.GetMessage()); err != nil {
+				log.Errorf("Failed to reboot with method %v: %v", method, err)
+			}
+		})
+		rs.mu.Unlock()
+		return nil
+	}
+	rs.mu.Unlock()
+	return rs.issue(method, req.GetMessage())
+}
+
+func (rs *rebootScheduler) issue(method gnoi_system_pb.RebootMethod, message string) error {
+	rs.mu.Lock()
+	rs.issued = true
+	rs.count++
+	rs.mu.Unlock()
+	common_utils.IncCounter(common_utils.GNOI_REBOOT)
+
+	err := issueReboot(method, message)
+
+	// The host goes down on success, unless the configuration is only reloaded
+	rs.mu.Lock()
+	rs.active = false
+	rs.issued = false
+	rs.timer = nil
+	rs.mu.Unlock()
+	return err
+}
+
+// cancel cancels the pending reboot, if any. It fails if the reboot is being
+// issued already.
+func (rs *rebootScheduler) cancel(message string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if !rs.active {
+		return nil
+	}
+	if rs.issued || rs.timer == nil || !rs.timer.Stop() {
+		return status.Errorf(codes.FailedPrecondition, "Reboot is already in progress")
+	}
+	log.V(1).Infof("Reboot scheduled at %v cancelled: %s", rs.when, message)
+	rs.active = false
+	rs.timer = nil
+	return nil
+}
+
+func (rs *rebootScheduler) status() *gnoi_system_pb.RebootStatusResponse {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	resp := &gnoi_system_pb.RebootStatusResponse{Active: rs.active, Count: rs.count}
+	if rs.active {
+		if wait := time.Until(rs.when); wait > 0 {
+			resp.Wait = uint64(wait)
+		}
+		resp.When = uint64(rs.when.UnixNano())
+		resp.Reason = rs.reason
+	}
+	return resp
+}
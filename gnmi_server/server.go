@@ -2,9 +2,11 @@ package gnmi
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -20,6 +22,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
 	gnmi_extpb "github.com/openconfig/gnmi/proto/gnmi_ext"
+	gnoi_file_pb "github.com/openconfig/gnoi/file"
+	gnoi_os_pb "github.com/openconfig/gnoi/os"
 	gnoi_system_pb "github.com/openconfig/gnoi/system"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
@@ -48,10 +52,12 @@ type Server struct {
 	SaveStartupConfig func() error
 	// ReqFromMaster point to a function that is called to verify if the request
 	// comes from a master controller.
-	ReqFromMaster func(req *gnmipb.SetRequest, masterEID *uint128) error
-	masterEID     uint128
+	ReqFromMaster func(req *gnmipb.SetRequest, masterEIDs masterEIDs) error
+	masterEIDs    masterEIDs
 	// UnimplementedSystemServer is embedded to satisfy SystemServer interface requirements
 	gnoi_system_pb.UnimplementedSystemServer
+	// UnimplementedOSServer is embedded to satisfy OSServer interface requirements
+	gnoi_os_pb.UnimplementedOSServer
 }
 
 type AuthTypes map[string]bool
@@ -69,11 +75,36 @@ type Config struct {
 	ZmqPort             string
 	IdleConnDuration    int
 	ConfigTableName     string
+	// Certificate identity sources of cert authentication, in order of
+	// preference, the common name if empty.
+	CertIdentitySources []string
+	// Authorizer checks the roles of authenticated users, all requests
+	// are allowed if nil.
+	Authorizer Authorizer
+	// Directories accessible through the gNOI File service, which is not
+	// served if empty, and requires an Authorizer and user authentication.
+	FileDirs []string
+	// gNOI OS Install rejects images sent without their SHA-256 if set.
+	RequireImageHash bool
+	// Directory where gNOI OS Install stages images, which must be mounted
+	// from HostImageDir of the host. Install is rejected if empty.
+	ImageDir string
+	// Path of ImageDir on the host, passed to the host image installer.
+	// ImageDir is mounted at the same path if empty.
+	HostImageDir string
+	// Maximum size of the images received by gNOI OS Install,
+	// DefaultMaxImageSize if 0.
+	MaxImageSize int64
 }
 
 var AuthLock sync.Mutex
 var maMu sync.Mutex
 
+// Server whose masters are reported by the OTHERS/master_arbitration path,
+// the last one created, guarded by maMu.
+var maServer *Server
+var registerMasterArbitration sync.Once
+
 func (i AuthTypes) String() string {
 	if i["none"] {
 		return ""
@@ -156,20 +187,39 @@ func NewServer(config *Config, opts []grpc.ServerOption) (*Server, error) {
 		// ReqFromMaster point to a function that is called to verify if
 		// the request comes from a master controller.
 		ReqFromMaster: ReqFromMasterDisabledMA,
-		masterEID:     uint128{High: 0, Low: 0},
+		masterEIDs:    masterEIDs{"": {High: 0, Low: 0}},
 	}
 	var err error
 	if srv.config.Port < 0 {
 		srv.config.Port = 0
 	}
+	if len(srv.config.FileDirs) != 0 {
+		// Files would otherwise be readable and writable by anyone
+		if srv.config.Authorizer == nil {
+			return nil, errors.New("gNOI File service requires an authorization policy")
+		}
+		if !srv.config.UserAuth.Any() {
+			return nil, errors.New("gNOI File service requires user authentication")
+		}
+	}
 	srv.lis, err = net.Listen("tcp", fmt.Sprintf(":%d", srv.config.Port))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open listener port %d: %v", srv.config.Port, err)
 	}
+	maMu.Lock()
+	maServer = srv
+	maMu.Unlock()
+	registerMasterArbitration.Do(func() {
+		sdc.RegisterNonDbPath([]string{"OTHERS", "master_arbitration"}, masterArbitrationJson)
+	})
 	gnmipb.RegisterGNMIServer(srv.s, srv)
 	spb_jwt_gnoi.RegisterSonicJwtServiceServer(srv.s, srv)
 	if srv.config.EnableTranslibWrite || srv.config.EnableNativeWrite {
 		gnoi_system_pb.RegisterSystemServer(srv.s, srv)
+		gnoi_os_pb.RegisterOSServer(srv.s, srv)
+		if len(srv.config.FileDirs) != 0 {
+			gnoi_file_pb.RegisterFileServer(srv.s, &FileServer{srv: srv})
+		}
 	}
 	if srv.config.EnableTranslibWrite {
 		spb_gnoi.RegisterSonicServiceServer(srv.s, srv)
@@ -241,7 +291,7 @@ func authenticate(config *Config, ctx context.Context) (context.Context, error)
 		}
 	}
 	if !success && config.UserAuth.Enabled("cert") {
-		ctx, err = ClientCertAuthenAndAuthor(ctx, config.ConfigTableName)
+		ctx, err = ClientCertAuthenAndAuthor(ctx, config.ConfigTableName, config.CertIdentitySources...)
 		if err == nil {
 			success = true
 		}
@@ -274,15 +324,10 @@ func (s *Server) Subscribe(stream gnmipb.GNMI_SubscribeServer) error {
 		return grpc.Errorf(codes.InvalidArgument, "failed to get peer address")
 	}
 
-	/* TODO: authorize the user
-	msg, ok := credentials.AuthorizeUser(ctx)
-	if !ok {
-		log.Infof("denied a Set request: %v", msg)
-		return nil, status.Error(codes.PermissionDenied, msg)
-	}
-	*/
-
 	c := NewClient(pr.Addr)
+	c.setAuthorizer(func(req *AuthzRequest) error {
+		return authorize(s.config, ctx, req)
+	})
 
 	c.setLogLevel(s.config.LogLevel)
 	c.setConnectionManager(s.config.Threshold)
@@ -339,7 +384,7 @@ func ParseOrigin(paths []*gnmipb.Path) (string, error) {
 }
 
 func IsNativeOrigin(origin string) bool {
-	return origin == "sonic-db"
+	return sdc.IsNativeOrigin(origin)
 }
 
 // Get implements the Get RPC in gNMI spec.
@@ -351,9 +396,10 @@ func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetRe
 		return nil, err
 	}
 
-	if req.GetType() != gnmipb.GetRequest_ALL {
+	dataType := req.GetType()
+	if _, ok := gnmipb.GetRequest_DataType_name[int32(dataType)]; !ok {
 		common_utils.IncCounter(common_utils.GNMI_GET_FAIL)
-		return nil, status.Errorf(codes.Unimplemented, "unsupported request type: %s", gnmipb.GetRequest_DataType_name[int32(req.GetType())])
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported request type: %v", dataType)
 	}
 
 	if err = s.checkEncodingAndModel(req.GetEncoding(), req.GetUseModels()); err != nil {
@@ -374,12 +420,23 @@ func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetRe
 	encoding := req.GetEncoding()
 	log.V(2).Infof("GetRequest paths: %v", paths)
 
+	authzOrigin := origin
+	if authzOrigin == "" {
+		authzOrigin, _ = ParseOrigin(paths)
+	}
+	authzReq := &AuthzRequest{Rpc: rpcGnmiGet, Origin: authzOrigin, Prefix: prefix, Paths: paths}
+	if err = authorize(s.config, ctx, authzReq); err != nil {
+		common_utils.IncCounter(common_utils.GNMI_GET_FAIL)
+		return nil, err
+	}
+
 	var dc sdc.Client
+	dataTypeOpt := sdc.DataTypeOption{DataType: dataType}
 
 	if target == "OTHERS" {
-		dc, err = sdc.NewNonDbClient(paths, prefix)
+		dc, err = sdc.NewNonDbClient(paths, prefix, dataTypeOpt)
 	} else if _, ok, _, _ := sdc.IsTargetDb(target); ok {
-		dc, err = sdc.NewDbClient(paths, prefix)
+		dc, err = sdc.NewDbClient(paths, prefix, dataTypeOpt)
 	} else {
 		if origin == "" {
 			origin, err = ParseOrigin(paths)
@@ -388,9 +445,9 @@ func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetRe
 			}
 		}
 		if check := IsNativeOrigin(origin); check {
-			dc, err = sdc.NewMixedDbClient(paths, prefix, origin, encoding, s.config.ZmqPort)
+			dc, err = sdc.NewMixedDbClient(paths, prefix, origin, encoding, s.config.ZmqPort, dataTypeOpt)
 		} else {
-			dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions)
+			dc, err = sdc.NewTranslClient(prefix, paths, ctx, extensions, dataTypeOpt)
 		}
 	}
 
@@ -399,12 +456,13 @@ func (s *Server) Get(ctx context.Context, req *gnmipb.GetRequest) (*gnmipb.GetRe
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 	defer dc.Close()
-	notifications := make([]*gnmipb.Notification, len(paths))
 	spbValues, err := dc.Get(nil)
 	if err != nil {
 		common_utils.IncCounter(common_utils.GNMI_GET_FAIL)
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
+	// Paths holding no data of the requested type yield no value
+	notifications := make([]*gnmipb.Notification, len(spbValues))
 
 	for index, spbValue := range spbValues {
 		update := &gnmipb.Update{
@@ -441,7 +499,7 @@ func SaveOnSetEnabled() error {
 func saveOnSetDisabled() error { return nil }
 
 func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetResponse, error) {
-	e := s.ReqFromMaster(req, &s.masterEID)
+	e := s.ReqFromMaster(req, s.masterEIDs)
 	if e != nil {
 		return nil, e
 	}
@@ -481,6 +539,11 @@ func (s *Server) Set(ctx context.Context, req *gnmipb.SetRequest) (*gnmipb.SetRe
 			return nil, err
 		}
 	}
+	authzReq := &AuthzRequest{Rpc: rpcGnmiSet, Write: true, Origin: origin, Prefix: prefix, Paths: paths}
+	if err = authorize(s.config, ctx, authzReq); err != nil {
+		common_utils.IncCounter(common_utils.GNMI_SET_FAIL)
+		return nil, err
+	}
 	if check := IsNativeOrigin(origin); check {
 		if s.config.EnableNativeWrite == false {
 			common_utils.IncCounter(common_utils.GNMI_SET_FAIL)
@@ -595,8 +658,8 @@ func (s *Server) Capabilities(ctx context.Context, req *gnmipb.CapabilityRequest
 }
 
 type uint128 struct {
-	High uint64
-	Low  uint64
+	High uint64 `json:"high"`
+	Low  uint64 `json:"low"`
 }
 
 func (lh *uint128) Compare(rh *uint128) int {
@@ -619,11 +682,43 @@ func (lh *uint128) Compare(rh *uint128) int {
 	return 0
 }
 
+// masterEIDs holds the election ID of the current master of each role. The
+// default role, used when the request has no role, is the empty string.
+type masterEIDs map[string]uint128
+
+// toJson returns the masters of all roles, for the OTHERS/master_arbitration
+// Get path.
+func (m masterEIDs) toJson() ([]byte, error) {
+	type master struct {
+		Role       string  `json:"role"`
+		ElectionId uint128 `json:"election_id"`
+	}
+
+	maMu.Lock()
+	masters := make([]master, 0, len(m))
+	for role, eid := range m {
+		masters = append(masters, master{Role: role, ElectionId: eid})
+	}
+	maMu.Unlock()
+
+	sort.Slice(masters, func(i, j int) bool { return masters[i].Role < masters[j].Role })
+	return json.Marshal(masters)
+}
+
+// masterArbitrationJson returns the masters of maServer.
+func masterArbitrationJson() ([]byte, error) {
+	maMu.Lock()
+	srv := maServer
+	maMu.Unlock()
+	return srv.masterEIDs.toJson()
+}
+
 // ReqFromMasterEnabledMA returns true if the request is sent by the master
-// controller.
-func ReqFromMasterEnabledMA(req *gnmipb.SetRequest, masterEID *uint128) error {
+// controller of the role of the request.
+func ReqFromMasterEnabledMA(req *gnmipb.SetRequest, masterEIDs masterEIDs) error {
 	// Read the election_id.
 	reqEID := uint128{High: 0, Low: 0}
+	role := ""
 	hasMaExt := false
 	// It can be one of many extensions, so iterate through them to find it.
 	for _, e := range req.GetExtension() {
@@ -638,34 +733,31 @@ func ReqFromMasterEnabledMA(req *gnmipb.SetRequest, masterEID *uint128) error {
 			return status.Errorf(codes.InvalidArgument, "MA: ElectionId missing")
 		}
 
-		if ma.Role != nil {
-			// Role will be implemented later.
-			return status.Errorf(codes.Unimplemented, "MA: Role is not implemented")
-		}
-
 		reqEID = uint128{High: ma.ElectionId.High, Low: ma.ElectionId.Low}
+		role = ma.GetRole().GetId()
 		// Use the election ID that is in the last extension, so, no 'break' here.
 	}
 
 	if !hasMaExt {
-		log.V(0).Infof("MA: No Master Arbitration in setRequest extension, masterEID %v is not updated", masterEID)
+		log.V(0).Infof("MA: No Master Arbitration in setRequest extension, masterEIDs are not updated")
 		return nil
 	}
 
 	maMu.Lock()
 	defer maMu.Unlock()
+	masterEID := masterEIDs[role]
 	switch masterEID.Compare(&reqEID) {
 	case 1: // This Election ID is smaller than the known Master Election ID.
-		return status.Errorf(codes.PermissionDenied, "Election ID is smaller than the current master. Rejected. Master EID: %v. Current EID: %v.", masterEID, reqEID)
+		return status.Errorf(codes.PermissionDenied, "Election ID is smaller than the current master of role %q. Rejected. Master EID: %v. Current EID: %v.", role, masterEID, reqEID)
 	case -1: // New Master Election ID received!
-		log.V(0).Infof("New master has been elected with %v\n", reqEID)
-		*masterEID = reqEID
+		log.V(0).Infof("New master of role %q has been elected with %v\n", role, reqEID)
+		masterEIDs[role] = reqEID
 	}
 	return nil
 }
 
 // ReqFromMasterDisabledMA always returns true. It is used when Master Arbitration
 // is disabled.
-func ReqFromMasterDisabledMA(req *gnmipb.SetRequest, masterEID *uint128) error {
+func ReqFromMasterDisabledMA(req *gnmipb.SetRequest, masterEIDs masterEIDs) error {
 	return nil
 }
@@ -3,11 +3,24 @@ package gnmi
 // server_test covers gNMI get, subscribe (stream and poll) test
 // Prerequisite: redis-server should be running.
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/user"
@@ -32,6 +45,7 @@ import (
 	"github.com/sonic-net/sonic-gnmi/test_utils"
 	testcert "github.com/sonic-net/sonic-gnmi/testdata/tls"
 
+	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/go-redis/redis"
 	"github.com/golang/protobuf/proto"
 	"github.com/kylelemons/godebug/pretty"
@@ -41,11 +55,13 @@ import (
 	"github.com/openconfig/gnmi/value"
 	"github.com/openconfig/ygot/ygot"
 
+	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
@@ -58,7 +74,10 @@ import (
 	"github.com/jipanyang/gnxi/utils/xpath"
 	cacheclient "github.com/openconfig/gnmi/client"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	gnoi_file_pb "github.com/openconfig/gnoi/file"
+	gnoi_os_pb "github.com/openconfig/gnoi/os"
 	gnoi_system_pb "github.com/openconfig/gnoi/system"
+	gnoi_types_pb "github.com/openconfig/gnoi/types"
 	"github.com/sonic-net/sonic-gnmi/common_utils"
 	"github.com/sonic-net/sonic-gnmi/swsscommon"
 )
@@ -730,9 +749,11 @@ func prepareDbTranslib(t *testing.T) {
 
 // subscriptionQuery represent the input to create an gnmi.Subscription instance.
 type subscriptionQuery struct {
-	Query          []string
-	SubMode        pb.SubscriptionMode
-	SampleInterval uint64
+	Query             []string
+	SubMode           pb.SubscriptionMode
+	SampleInterval    uint64
+	HeartbeatInterval uint64
+	SuppressRedundant bool
 }
 
 func pathToString(q client.Path) string {
@@ -766,9 +787,11 @@ func createQuery(subListMode pb.SubscriptionList_Mode, target string, queries []
 		s.Subscribe.Subscription = append(
 			s.Subscribe.Subscription,
 			&pb.Subscription{
-				Path:           pp,
-				Mode:           qq.SubMode,
-				SampleInterval: qq.SampleInterval,
+				Path:              pp,
+				Mode:              qq.SubMode,
+				SampleInterval:    qq.SampleInterval,
+				HeartbeatInterval: qq.HeartbeatInterval,
+				SuppressRedundant: qq.SuppressRedundant,
 			})
 	}
 
@@ -829,6 +852,15 @@ func createCountersDbQueryOnChangeMode(t *testing.T, paths ...string) client.Que
 		false)
 }
 
+// createCountersDbQueryOptions creates a query of a single subscription with options.
+func createCountersDbQueryOptions(t *testing.T, sq subscriptionQuery) client.Query {
+	return createQueryOrFail(t,
+		pb.SubscriptionList_STREAM,
+		"COUNTERS_DB",
+		[]subscriptionQuery{sq},
+		false)
+}
+
 // createCountersDbQuerySampleMode creates a query with SAMPLE mode.
 func createCountersDbQuerySampleMode(t *testing.T, interval time.Duration, updateOnly bool, paths ...string) client.Query {
 	return createQueryOrFail(t,
@@ -1331,6 +1363,24 @@ func runGnmiTestGet(t *testing.T, namespace string) {
 			wantRetCode: codes.OK,
 			wantRespVal: "2",
 			valTest:     true,
+		}, {
+			desc:       "get COUNTERS[name=Ethernet68] SAI_PORT_STAT_PFC_7_RX_PKTS",
+			pathTarget: "COUNTERS_DB",
+			textPbPath: `
+					elem: <name: "COUNTERS" key: <key: "name" value: "Ethernet68" > >
+					elem: <name: "SAI_PORT_STAT_PFC_7_RX_PKTS" >
+				`,
+			wantRetCode: codes.OK,
+			wantRespVal: "2",
+			valTest:     true,
+		}, {
+			desc:       "get COUNTERS with multiple keys",
+			pathTarget: "COUNTERS_DB",
+			textPbPath: `
+					elem: <name: "COUNTERS" key: <key: "name" value: "Ethernet68" > key: <key: "index" value: "0" > >
+					elem: <name: "SAI_PORT_STAT_PFC_7_RX_PKTS" >
+				`,
+			wantRetCode: codes.NotFound,
 		}, {
 			desc:       "get COUNTERS:Ethernet68 Pfcwd",
 			pathTarget: "COUNTERS_DB",
@@ -1503,6 +1553,102 @@ func TestGnmiGetMultiNs(t *testing.T) {
 	s.Stop()
 }
 
+func TestGnmiGetDataType(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	ns, _ := sdcfg.GetDbDefaultNamespace()
+	prepareDb(t, ns)
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tds := []struct {
+		desc        string
+		target      string
+		textPbPath  string
+		dataType    pb.GetRequest_DataType
+		wantRetCode codes.Code
+		wantNotifs  int
+	}{
+		{
+			desc:        "CONFIG from CONFIG_DB",
+			target:      "CONFIG_DB",
+			textPbPath:  `elem: <name: "PORT" >`,
+			dataType:    pb.GetRequest_CONFIG,
+			wantRetCode: codes.OK,
+			wantNotifs:  1,
+		}, {
+			desc:        "STATE from CONFIG_DB",
+			target:      "CONFIG_DB",
+			textPbPath:  `elem: <name: "PORT" >`,
+			dataType:    pb.GetRequest_STATE,
+			wantRetCode: codes.OK,
+			wantNotifs:  0,
+		}, {
+			desc:        "STATE from COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			textPbPath:  `elem: <name: "COUNTERS_PORT_NAME_MAP" >`,
+			dataType:    pb.GetRequest_STATE,
+			wantRetCode: codes.OK,
+			wantNotifs:  1,
+		}, {
+			desc:        "OPERATIONAL from COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			textPbPath:  `elem: <name: "COUNTERS_PORT_NAME_MAP" >`,
+			dataType:    pb.GetRequest_OPERATIONAL,
+			wantRetCode: codes.OK,
+			wantNotifs:  1,
+		}, {
+			desc:        "CONFIG from COUNTERS_DB",
+			target:      "COUNTERS_DB",
+			textPbPath:  `elem: <name: "COUNTERS_PORT_NAME_MAP" >`,
+			dataType:    pb.GetRequest_CONFIG,
+			wantRetCode: codes.OK,
+			wantNotifs:  0,
+		}, {
+			desc:        "invalid data type",
+			target:      "COUNTERS_DB",
+			textPbPath:  `elem: <name: "COUNTERS_PORT_NAME_MAP" >`,
+			dataType:    pb.GetRequest_DataType(100),
+			wantRetCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			var pbPath pb.Path
+			if err := proto.UnmarshalText(td.textPbPath, &pbPath); err != nil {
+				t.Fatalf("error in unmarshaling path: %v %v", td.textPbPath, err)
+			}
+			req := &pb.GetRequest{
+				Prefix:   &pb.Path{Target: td.target},
+				Path:     []*pb.Path{&pbPath},
+				Type:     td.dataType,
+				Encoding: pb.Encoding_JSON_IETF,
+			}
+			resp, err := gClient.Get(ctx, req)
+			if status.Code(err) != td.wantRetCode {
+				t.Fatalf("got return code %v, want %v: %v", status.Code(err), td.wantRetCode, err)
+			}
+			if err == nil && len(resp.GetNotification()) != td.wantNotifs {
+				t.Errorf("got %d notifications, want %d", len(resp.GetNotification()), td.wantNotifs)
+			}
+		})
+	}
+}
+
 /*
 func TestGnmiGetTranslib(t *testing.T) {
 	//t.Log("Start server")
@@ -1929,6 +2075,34 @@ func runTestSubscribe(t *testing.T, namespace string) {
 				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
 			},
 		},
+		{
+			desc: "stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with update of another field",
+			q:    createCountersDbQueryOnChangeMode(t, "COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
+			updates: []tablePathValue{
+				{ //Other fields of the table should not trigger updates
+					dbName:    "COUNTERS_DB",
+					tableName: "COUNTERS",
+					tableKey:  "oid:0x1000000000039", // "Ethernet68": "oid:0x1000000000039",
+					delimitor: ":",
+					field:     "SAI_PORT_STAT_PFC_6_RX_PKTS",
+					value:     "7",
+				},
+				{
+					dbName:    "COUNTERS_DB",
+					tableName: "COUNTERS",
+					tableKey:  "oid:0x1000000000039", // "Ethernet68": "oid:0x1000000000039",
+					delimitor: ":",
+					field:     "SAI_PORT_STAT_PFC_7_RX_PKTS",
+					value:     "5", // be changed to 5 from 2
+				},
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "5"},
+			},
+		},
 		{
 			desc: "(use vendor alias) stream query for COUNTERS/[Ethernet68/1]/SAI_PORT_STAT_PFC_7_RX_PKTS with update of field value",
 			q:    createCountersDbQueryOnChangeMode(t, "COUNTERS", "Ethernet68/1", "SAI_PORT_STAT_PFC_7_RX_PKTS"),
@@ -2081,6 +2255,23 @@ func runTestSubscribe(t *testing.T, namespace string) {
 				client.Update{Path: []string{"COUNTERS", "Ethernet*", "Pfcwd"}, TS: time.Unix(0, 200), Val: countersEthernet68PfcwdAliasJsonUpdate},
 			},
 		},
+		{
+			desc: "once query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS",
+			q: createQueryOrFail(t,
+				pb.SubscriptionList_ONCE,
+				"COUNTERS_DB",
+				[]subscriptionQuery{
+					{
+						Query: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+					},
+				},
+				false),
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+			},
+		},
 		{
 			desc: "poll query for table COUNTERS_PORT_NAME_MAP with new field test_field",
 			poll: 3,
@@ -2625,6 +2816,109 @@ func runTestSubscribe(t *testing.T, namespace string) {
 				client.Update{Path: []string{"COUNTERS", "Ethernet*", "Pfcwd"}, TS: time.Unix(0, 200), Val: map[string]interface{}{}}, //empty update
 			},
 		},
+		{
+			desc:              "(suppress redundant) sample stream query for table key Ethernet*/Pfcwd with field value update",
+			generateIntervals: true,
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:             []string{"COUNTERS", "Ethernet*", "Pfcwd"},
+				SubMode:           pb.SubscriptionMode_SAMPLE,
+				SuppressRedundant: true,
+			}),
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // no value change, no update
+				createCountersTableSetUpdate("oid:0x1500000000091e", "PFC_WD_QUEUE_STATS_DEADLOCK_DETECTED", "1"),
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*", "Pfcwd"}, TS: time.Unix(0, 200), Val: countersEthernetWildPfcwdJson},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet*", "Pfcwd"}, TS: time.Unix(0, 200), Val: mergeStrMaps(countersEthernetWildPfcwdJson, countersEthernet68PfcwdAliasJsonUpdate)},
+			},
+		},
+		{
+			desc:              "(suppress redundant) sample stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS with 2 updates",
+			generateIntervals: true,
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:             []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+				SubMode:           pb.SubscriptionMode_SAMPLE,
+				SuppressRedundant: true,
+			}),
+			updates: []tablePathValue{
+				createCountersTableSetUpdate("oid:0x1000000000039", "SAI_PORT_STAT_PFC_7_RX_PKTS", "3"), // be changed to 3 from 2
+				createIntervalTickerUpdate(), // no value change, no update
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "3"},
+			},
+		},
+		{
+			desc:              "target defined stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS is sampled",
+			generateIntervals: true,
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:   []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+				SubMode: pb.SubscriptionMode_TARGET_DEFINED,
+			}),
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // no value change but imitate interval ticker
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+			},
+		},
+		{
+			desc: "target defined stream query for table COUNTERS_PORT_NAME_MAP is on change",
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:   []string{"COUNTERS_PORT_NAME_MAP"},
+				SubMode: pb.SubscriptionMode_TARGET_DEFINED,
+			}),
+			updates: []tablePathValue{{
+				dbName:    "COUNTERS_DB",
+				tableName: "COUNTERS_PORT_NAME_MAP",
+				field:     "test_field",
+				value:     "test_value",
+			}},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS_PORT_NAME_MAP"}, TS: time.Unix(0, 200), Val: countersPortNameMapJson},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS_PORT_NAME_MAP"}, TS: time.Unix(0, 200), Val: countersPortNameMapJsonUpdate},
+			},
+		},
+		{
+			desc:              "(heartbeat) stream query for COUNTERS/Ethernet68/SAI_PORT_STAT_PFC_7_RX_PKTS without update",
+			generateIntervals: true,
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:             []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"},
+				SubMode:           pb.SubscriptionMode_ON_CHANGE,
+				HeartbeatInterval: uint64(sdc.MinSampleInterval),
+			}),
+			updates: []tablePathValue{
+				createIntervalTickerUpdate(), // no value change but imitate heartbeat ticker
+			},
+			wantNoti: []client.Notification{
+				client.Connected{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+				client.Sync{},
+				client.Update{Path: []string{"COUNTERS", "Ethernet68", "SAI_PORT_STAT_PFC_7_RX_PKTS"}, TS: time.Unix(0, 200), Val: "2"},
+			},
+		},
+		{
+			desc: "use invalid heartbeat interval",
+			q: createCountersDbQueryOptions(t, subscriptionQuery{
+				Query:             []string{"COUNTERS", "Ethernet1"},
+				SubMode:           pb.SubscriptionMode_ON_CHANGE,
+				HeartbeatInterval: uint64(10 * time.Millisecond),
+			}),
+			updates:    []tablePathValue{},
+			wantSubErr: fmt.Errorf("rpc error: code = InvalidArgument desc = invalid heartbeat interval: 10ms. It cannot be less than %v", sdc.MinSampleInterval),
+			wantNoti:   []client.Notification{},
+		},
 	}
 
 	sdc.NeedMock = true
@@ -2772,6 +3066,129 @@ func TestGnmiSubscribeMultiNs(t *testing.T) {
 	s.Stop()
 }
 
+func TestGnmiSubscribeAliases(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	ns, _ := sdcfg.GetDbDefaultNamespace()
+	prepareDb(t, ns)
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	targetAddr := "127.0.0.1:8081"
+	conn, err := grpc.Dial(targetAddr, opts...)
+	if err != nil {
+		t.Fatalf("Dialing to %q failed: %v", targetAddr, err)
+	}
+	defer conn.Close()
+
+	gClient := pb.NewGNMIClient(conn)
+
+	portMapPath := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS_PORT_NAME_MAP"}}}
+	portPath := &pb.Path{Elem: []*pb.PathElem{{Name: "COUNTERS"}, {Name: "Ethernet68"}}}
+	subscribeReq := func(useAliases bool) *pb.SubscribeRequest {
+		path := portMapPath
+		if useAliases {
+			path = portPath
+		}
+		return &pb.SubscribeRequest{
+			Request: &pb.SubscribeRequest_Subscribe{
+				Subscribe: &pb.SubscriptionList{
+					Prefix:       &pb.Path{Target: "COUNTERS_DB"},
+					Subscription: []*pb.Subscription{{Path: path}},
+					Mode:         pb.SubscriptionList_POLL,
+					UseAliases:   useAliases,
+				},
+			},
+		}
+	}
+	aliasesReq := func(name string) *pb.SubscribeRequest {
+		return &pb.SubscribeRequest{
+			Request: &pb.SubscribeRequest_Aliases{
+				Aliases: &pb.AliasList{
+					Alias: []*pb.Alias{{
+						Alias: name,
+						Path:  &pb.Path{Target: "COUNTERS_DB", Elem: portMapPath.GetElem()},
+					}},
+				},
+			},
+		}
+	}
+
+	tds := []struct {
+		desc        string
+		reqs        []*pb.SubscribeRequest
+		wantRetCode codes.Code
+		wantAlias   string // alias announced by the server
+		wantPrefix  string // alias used in the update prefix
+		wantElems   int    // elements of the update path left after the alias
+	}{
+		{
+			desc:        "client-defined alias",
+			reqs:        []*pb.SubscribeRequest{aliasesReq("#portmap"), subscribeReq(false)},
+			wantRetCode: codes.OK,
+			wantPrefix:  "#portmap",
+		},
+		{
+			desc:        "server-defined alias",
+			reqs:        []*pb.SubscribeRequest{subscribeReq(true)},
+			wantRetCode: codes.OK,
+			wantAlias:   "#s1",
+			wantPrefix:  "#s1",
+			wantElems:   1,
+		},
+		{
+			desc:        "invalid client-defined alias",
+			reqs:        []*pb.SubscribeRequest{aliasesReq("portmap"), subscribeReq(false)},
+			wantRetCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			stream, err := gClient.Subscribe(ctx)
+			if err != nil {
+				t.Fatalf("Subscribe failed: %v", err)
+			}
+			for _, req := range td.reqs {
+				if err = stream.Send(req); err != nil {
+					t.Fatalf("Send failed: %v", err)
+				}
+			}
+
+			resp, err := stream.Recv()
+			if td.wantRetCode != codes.OK {
+				if status.Code(err) != td.wantRetCode {
+					t.Fatalf("got return code %v, want %v", status.Code(err), td.wantRetCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Recv failed: %v", err)
+			}
+			if td.wantAlias != "" {
+				n := resp.GetUpdate()
+				if n.GetAlias() != td.wantAlias || n.GetPrefix().GetTarget() != "COUNTERS_DB" {
+					t.Fatalf("got alias notification %v, want alias %v", n, td.wantAlias)
+				}
+				if resp, err = stream.Recv(); err != nil {
+					t.Fatalf("Recv failed: %v", err)
+				}
+			}
+			n := resp.GetUpdate()
+			if elems := n.GetPrefix().GetElem(); len(elems) != 1 || elems[0].GetName() != td.wantPrefix {
+				t.Fatalf("got prefix %v, want alias %v", n.GetPrefix(), td.wantPrefix)
+			}
+			if len(n.GetUpdate()) != 1 || len(n.GetUpdate()[0].GetPath().GetElem()) != td.wantElems {
+				t.Fatalf("got updates %v, want one update with %d elements after the alias", n.GetUpdate(), td.wantElems)
+			}
+		})
+	}
+}
+
 func TestCapabilities(t *testing.T) {
 	//t.Log("Start server")
 	s := createServer(t, 8085)
@@ -3274,6 +3691,19 @@ func TestCPUUtilization(t *testing.T) {
 				client.Sync{},
 			},
 		},
+		{
+			desc: "once query for CPU Utilization",
+			q: client.Query{
+				Target:  "OTHERS",
+				Type:    client.Once,
+				Queries: []client.Path{{"platform", "cpu"}},
+				TLS:     &tls.Config{InsecureSkipVerify: true},
+			},
+			want: []client.Notification{
+				client.Connected{},
+				client.Sync{},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -3729,22 +4159,106 @@ func TestClient(t *testing.T) {
 	s.Stop()
 }
 
-func TestTableData2MsiUseKey(t *testing.T) {
-	tblPath := sdc.CreateTablePath("STATE_DB", "NEIGH_STATE_TABLE", "|", "10.0.0.57")
-	newMsi := make(map[string]interface{})
-	sdc.TableData2Msi(&tblPath, true, nil, &newMsi)
-	newMsiData, _ := json.MarshalIndent(newMsi, "", "  ")
-	t.Logf(string(newMsiData))
-	expectedMsi := map[string]interface{}{
-		"10.0.0.57": map[string]interface{}{
-			"peerType": "e-BGP",
-			"state":    "Established",
-		},
+func TestEventsPoll(t *testing.T) {
+	// sonic-host:device-test-event is a test event.
+	// Events client will drop it on floor.
+	events := []sdc.Evt_rcvd{
+		{"{\"sonic-events-bgp:bgp-state\":{\"ip\":\"10.0.0.1\",\"status\":\"down\"}}", 0, 777},
+		{"{\"sonic-host:device-test-event\"", 0, 677},
+		{"{\"sonic-events-bgp:bgp-state\":{\"ip\":\"10.0.0.1\",\"status\":\"up\"}}", 0, 577},
 	}
-	expectedMsiData, _ := json.MarshalIndent(expectedMsi, "", "  ")
-	t.Logf(string(expectedMsiData))
+	var mutexIdx sync.Mutex
+	event_index := 0
 
-	if !reflect.DeepEqual(newMsi, expectedMsi) {
+	mock1 := gomonkey.ApplyFunc(sdc.C_init_subs, func(use_cache bool) unsafe.Pointer {
+		return nil
+	})
+	defer mock1.Reset()
+	mock2 := gomonkey.ApplyFunc(sdc.C_recv_evt, func(h unsafe.Pointer) (int, sdc.Evt_rcvd) {
+		mutexIdx.Lock()
+		if event_index < len(events) {
+			evt := events[event_index]
+			event_index++
+			mutexIdx.Unlock()
+			return 0, evt
+		}
+		mutexIdx.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		return -1, sdc.Evt_rcvd{}
+	})
+	defer mock2.Reset()
+	mock3 := gomonkey.ApplyFunc(sdc.C_deinit_subs, func(h unsafe.Pointer) {})
+	defer mock3.Reset()
+
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	q := createQueryOrFail(t,
+		pb.SubscriptionList_POLL,
+		"EVENTS",
+		[]subscriptionQuery{
+			{
+				Query: []string{"all"},
+			},
+		},
+		false)
+	q.Addrs = []string{"127.0.0.1:8081"}
+	c := client.New()
+	defer c.Close()
+
+	var mutexNoti sync.Mutex
+	var gotUpdates, gotSyncs int
+	q.NotificationHandler = func(n client.Notification) error {
+		mutexNoti.Lock()
+		defer mutexNoti.Unlock()
+		switch n.(type) {
+		case client.Update:
+			gotUpdates++
+		case client.Sync:
+			gotSyncs++
+		}
+		return nil
+	}
+	if err := c.Subscribe(context.Background(), q); err != nil {
+		t.Fatalf("c.Subscribe(): got error %v, expected nil", err)
+	}
+
+	// Events received after the first poll are cached until the next ones
+	time.Sleep(500 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if err := c.Poll(); err != nil {
+			t.Errorf("c.Poll(): got error %v, expected nil", err)
+		}
+	}
+
+	mutexNoti.Lock()
+	defer mutexNoti.Unlock()
+	// -1 to discount test event, which receiver would drop.
+	if gotUpdates != len(events)-1 {
+		t.Errorf("got %d updates, want %d", gotUpdates, len(events)-1)
+	}
+	if gotSyncs != 3 {
+		t.Errorf("got %d sync responses, want 3", gotSyncs)
+	}
+}
+
+func TestTableData2MsiUseKey(t *testing.T) {
+	tblPath := sdc.CreateTablePath("STATE_DB", "NEIGH_STATE_TABLE", "|", "10.0.0.57")
+	newMsi := make(map[string]interface{})
+	sdc.TableData2Msi(&tblPath, true, nil, &newMsi)
+	newMsiData, _ := json.MarshalIndent(newMsi, "", "  ")
+	t.Logf(string(newMsiData))
+	expectedMsi := map[string]interface{}{
+		"10.0.0.57": map[string]interface{}{
+			"peerType": "e-BGP",
+			"state":    "Established",
+		},
+	}
+	expectedMsiData, _ := json.MarshalIndent(expectedMsi, "", "  ")
+	t.Logf(string(expectedMsiData))
+
+	if !reflect.DeepEqual(newMsi, expectedMsi) {
 		t.Errorf("Msi data does not match for use key = true")
 	}
 }
@@ -4058,8 +4572,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid0 := maExt0.GetMasterArbitration().GetElectionId()
 		expectedEID0 := uint128{High: reqEid0.GetHigh(), Low: reqEid0.GetLow()}
-		if s.masterEID.Compare(&expectedEID0) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID0, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID0) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID0, masterEIDOf(s, ""))
 		}
 	})
 	// After this test ElectionID is one.
@@ -4076,8 +4590,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid0 := maExt0.GetMasterArbitration().GetElectionId()
 		expectedEID0 := uint128{High: reqEid0.GetHigh(), Low: reqEid0.GetLow()}
-		if s.masterEID.Compare(&expectedEID0) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID0, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID0) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID0, masterEIDOf(s, ""))
 		}
 		req = &pb.SetRequest{
 			Prefix: &pb.Path{Elem: []*pb.PathElem{{Name: "interfaces"}}},
@@ -4091,8 +4605,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid1 := maExt1.GetMasterArbitration().GetElectionId()
 		expectedEID1 := uint128{High: reqEid1.GetHigh(), Low: reqEid1.GetLow()}
-		if s.masterEID.Compare(&expectedEID1) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID1, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID1) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID1, masterEIDOf(s, ""))
 		}
 	})
 	// Multiple ElectionIDs with the last being one.
@@ -4113,8 +4627,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid1 := maExt1.GetMasterArbitration().GetElectionId()
 		expectedEID1 := uint128{High: reqEid1.GetHigh(), Low: reqEid1.GetLow()}
-		if s.masterEID.Compare(&expectedEID1) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID1, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID1) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID1, masterEIDOf(s, ""))
 		}
 	})
 	// ElectionIDs with the high word set to 1 and low word to 0.
@@ -4135,8 +4649,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid10 := maExt1H0L.GetMasterArbitration().GetElectionId()
 		expectedEID10 := uint128{High: reqEid10.GetHigh(), Low: reqEid10.GetLow()}
-		if s.masterEID.Compare(&expectedEID10) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID10) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, masterEIDOf(s, ""))
 		}
 	})
 	// As the ElectionID is one, a request with ElectionID==0 will fail.
@@ -4162,8 +4676,8 @@ func TestMasterArbitration(t *testing.T) {
 		}
 		reqEid10 := maExt1H0L.GetMasterArbitration().GetElectionId()
 		expectedEID10 := uint128{High: reqEid10.GetHigh(), Low: reqEid10.GetLow()}
-		if s.masterEID.Compare(&expectedEID10) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID10) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, masterEIDOf(s, ""))
 		}
 		req = &pb.SetRequest{
 			Prefix: &pb.Path{Elem: []*pb.PathElem{{Name: "interfaces"}}},
@@ -4176,12 +4690,91 @@ func TestMasterArbitration(t *testing.T) {
 		if err != nil {
 			t.Fatal("Expected a successful set call.")
 		}
-		if s.masterEID.Compare(&expectedEID10) != 0 {
-			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, s.masterEID)
+		if masterEIDOf(s, "").Compare(&expectedEID10) != 0 {
+			t.Fatalf("Master EID update failed. Want %v, got %v", expectedEID10, masterEIDOf(s, ""))
 		}
 	})
 }*/
 
+// masterEIDOf returns the election ID of the master of role of s.
+func masterEIDOf(s *Server, role string) *uint128 {
+	maMu.Lock()
+	defer maMu.Unlock()
+	eid := s.masterEIDs[role]
+	return &eid
+}
+
+func TestMasterArbitrationRoles(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	maExt := func(role string, high, low uint64) *ext_pb.Extension {
+		ma := &ext_pb.MasterArbitration{
+			ElectionId: &ext_pb.Uint128{High: high, Low: low},
+		}
+		if role != "" {
+			ma.Role = &ext_pb.Role{Id: role}
+		}
+		return &ext_pb.Extension{
+			Ext: &ext_pb.Extension_MasterArbitration{MasterArbitration: ma},
+		}
+	}
+
+	tds := []struct {
+		desc        string
+		ext         *ext_pb.Extension
+		wantRetCode codes.Code
+	}{
+		{desc: "primary elected", ext: maExt("primary", 0, 2), wantRetCode: codes.OK},
+		{desc: "backup elected with smaller id", ext: maExt("backup", 0, 1), wantRetCode: codes.OK},
+		{desc: "default role elected", ext: maExt("", 0, 1), wantRetCode: codes.OK},
+		{desc: "primary rejected with smaller id", ext: maExt("primary", 0, 1), wantRetCode: codes.PermissionDenied},
+		{desc: "backup re-elected", ext: maExt("backup", 1, 0), wantRetCode: codes.OK},
+		{desc: "default role rejected with smaller id", ext: maExt("", 0, 0), wantRetCode: codes.PermissionDenied},
+	}
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			req := &pb.SetRequest{Extension: []*ext_pb.Extension{td.ext}}
+			err := ReqFromMasterEnabledMA(req, s.masterEIDs)
+			if status.Code(err) != td.wantRetCode {
+				t.Fatalf("got return code %v, want %v: %v", status.Code(err), td.wantRetCode, err)
+			}
+		})
+	}
+
+	wantEIDs := map[string]uint128{"": {0, 1}, "primary": {0, 2}, "backup": {1, 0}}
+	for role, want := range wantEIDs {
+		if masterEIDOf(s, role).Compare(&want) != 0 {
+			t.Errorf("Master EID of role %q: want %v, got %v", role, want, masterEIDOf(s, role))
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	conn, err := grpc.Dial("127.0.0.1:8081", opts...)
+	if err != nil {
+		t.Fatalf("Dialing failed: %v", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := pb.NewGNMIClient(conn).Get(ctx, &pb.GetRequest{
+		Prefix:   &pb.Path{Target: "OTHERS"},
+		Path:     []*pb.Path{{Elem: []*pb.PathElem{{Name: "master_arbitration"}}}},
+		Encoding: pb.Encoding_JSON_IETF,
+	})
+	if err != nil {
+		t.Fatalf("Get master_arbitration failed: %v", err)
+	}
+	wantJson := `[{"role":"","election_id":{"high":0,"low":1}},{"role":"backup","election_id":{"high":1,"low":0}},{"role":"primary","election_id":{"high":0,"low":2}}]`
+	gotJson := string(resp.GetNotification()[0].GetUpdate()[0].GetVal().GetJsonIetfVal())
+	if gotJson != wantJson {
+		t.Errorf("got master_arbitration %v, want %v", gotJson, wantJson)
+	}
+}
+
 func TestSaveOnSet(t *testing.T) {
 	// Fail client creation
 	fakeDBC := gomonkey.ApplyFuncReturn(ssc.NewDbusClient, nil, fmt.Errorf("Fail Create"))
@@ -4299,92 +4892,1344 @@ func CreateAuthorizationCtx() (context.Context, context.CancelFunc) {
 	swsscommon.DeleteDBConnector(configDb)
 }
 
-type MockServerStream struct {
-	grpc.ServerStream
-}
+func TestClientCertIdentityMapping(t *testing.T) {
+	if !swsscommon.SonicDBConfigIsInit() {
+		swsscommon.SonicDBConfigInitialize()
+	}
 
-func (x *MockServerStream) Context() context.Context {
-	return context.Background()
-}
+	var configDb = swsscommon.NewDBConnector("CONFIG_DB", uint(0), true)
+	var gnmiTable = swsscommon.NewTable(configDb, "GNMI_CLIENT_CERT")
+	defer swsscommon.DeleteDBConnector(configDb)
+	defer swsscommon.DeleteTable(gnmiTable)
 
-type MockPingServer struct {
-	MockServerStream
-}
+	defer func(ttl time.Duration) { certPatternCacheTTL = ttl }(certPatternCacheTTL)
+	certPatternCacheTTL = 0
 
-func (x *MockPingServer) Send(m *gnoi_system_pb.PingResponse) error {
-	return nil
-}
+	spiffeId, _ := url.Parse("spiffe://example.org/ns/telemetry/sa/collector")
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "certname1",
+			OrganizationalUnit: []string{"netops"},
+		},
+		DNSNames: []string{"collector1.example.org"},
+		URIs:     []*url.URL{spiffeId},
+	}
+	certCtx := func() context.Context {
+		p := peer.Peer{
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{
+					VerifiedChains: [][]*x509.Certificate{{cert}},
+				},
+			},
+		}
+		return peer.NewContext(context.Background(), &p)
+	}
 
-type MockTracerouteServer struct {
-	MockServerStream
-}
+	tests := []struct {
+		desc    string
+		sources []string
+		entries map[string]map[string]string
+		roles   []string
+	}{
+		{
+			desc:    "common name by default",
+			entries: map[string]map[string]string{"certname1": {"role": "role1"}},
+			roles:   []string{"role1"},
+		},
+		{
+			desc:    "multiple roles",
+			sources: []string{CertIdentityCN},
+			entries: map[string]map[string]string{"certname1": {"role": "role1,role2"}},
+			roles:   []string{"role1", "role2"},
+		},
+		{
+			desc:    "SAN URI exact",
+			sources: []string{CertIdentitySanURI},
+			entries: map[string]map[string]string{"spiffe://example.org/ns/telemetry/sa/collector": {"role": "collector"}},
+			roles:   []string{"collector"},
+		},
+		{
+			desc:    "SAN DNS glob",
+			sources: []string{CertIdentitySanDNS},
+			entries: map[string]map[string]string{"*.example.org": {"match": "glob", "role": "readonly"}},
+			roles:   []string{"readonly"},
+		},
+		{
+			desc:    "SAN URI regex merged with glob",
+			sources: []string{CertIdentitySanURI},
+			entries: map[string]map[string]string{
+				"spiffe://example.org/ns/[a-z]+/sa/collector": {"match": "regex", "role": "collector"},
+				"spiffe://example.org/ns/telemetry/*/*":       {"match": "glob", "role": "readonly,collector"},
+			},
+			roles: []string{"collector", "readonly"},
+		},
+		{
+			desc:    "first identity with a mapping wins",
+			sources: []string{CertIdentityCN, CertIdentityOU},
+			entries: map[string]map[string]string{"netops": {"role": "admin"}},
+			roles:   []string{"admin"},
+		},
+		{
+			desc:    "regex must match the whole identity",
+			sources: []string{CertIdentitySanDNS},
+			entries: map[string]map[string]string{"collector1": {"match": "regex", "role": "admin"}},
+		},
+		{
+			desc:    "source not configured",
+			sources: []string{CertIdentitySanDNS},
+			entries: map[string]map[string]string{"certname1": {"role": "role1"}},
+		},
+	}
 
-func (x *MockTracerouteServer) Send(m *gnoi_system_pb.TracerouteResponse) error {
-	return nil
-}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			configDb.Flushdb()
+			for key, fvs := range test.entries {
+				for field, value := range fvs {
+					gnmiTable.Hset(key, field, value)
+				}
+			}
+			ctx, err := ClientCertAuthenAndAuthor(certCtx(), "GNMI_CLIENT_CERT", test.sources...)
+			if test.roles == nil {
+				if err == nil {
+					t.Errorf("Expected authentication failure")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authentication failed: %v", err)
+			}
+			rc, _ := common_utils.GetContext(ctx)
+			if !reflect.DeepEqual(rc.Auth.Roles, test.roles) {
+				t.Errorf("Got roles %v, expected %v", rc.Auth.Roles, test.roles)
+			}
+		})
+	}
+	configDb.Flushdb()
 
-type MockSetPackageServer struct {
-	MockServerStream
-}
+	// Pattern entries are cached, exact entries are always read
+	certPatternCacheTTL = time.Hour
+	gnmiTable.Hset("*.example.org", "match", "glob")
+	gnmiTable.Hset("*.example.org", "role", "readonly")
+	gnmiTable.Hset("certname1", "role", "role1")
+	for _, source := range []string{CertIdentitySanDNS, CertIdentityCN} {
+		if _, err := ClientCertAuthenAndAuthor(certCtx(), "GNMI_CLIENT_CERT", source); err != nil {
+			t.Fatalf("Authentication failed: %v", err)
+		}
+	}
+	configDb.Flushdb()
+	if _, err := ClientCertAuthenAndAuthor(certCtx(), "GNMI_CLIENT_CERT", CertIdentitySanDNS); err != nil {
+		t.Errorf("Cached pattern entries should be used: %v", err)
+	}
+	if _, err := ClientCertAuthenAndAuthor(certCtx(), "GNMI_CLIENT_CERT", CertIdentityCN); err == nil {
+		t.Errorf("Expected authentication failure for removed exact entry")
+	}
+	certPatternCacheTTL = 0
+	if _, err := ClientCertAuthenAndAuthor(certCtx(), "GNMI_CLIENT_CERT", CertIdentitySanDNS); err == nil {
+		t.Errorf("Expired pattern entries should be read again")
+	}
 
-func (x *MockSetPackageServer) Send(m *gnoi_system_pb.SetPackageResponse) error {
-	return nil
+	if _, err := ParseCertIdentitySources("cn,san_uri"); err != nil {
+		t.Errorf("ParseCertIdentitySources failed: %v", err)
+	}
+	if _, err := ParseCertIdentitySources("cn,email"); err == nil {
+		t.Errorf("ParseCertIdentitySources should fail for unknown sources")
+	}
 }
 
-func (x *MockSetPackageServer) SendAndClose(m *gnoi_system_pb.SetPackageResponse) error {
-	return nil
-}
+func TestCertRevocationChecker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDer, _ := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	ca, _ := x509.ParseCertificate(caDer)
+
+	var ocspRevoked int64 = 3
+	ocspServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		tmpl := ocsp.Response{Status: ocsp.Good, SerialNumber: req.SerialNumber, ThisUpdate: time.Now(), NextUpdate: time.Now().Add(time.Hour)}
+		if req.SerialNumber.Int64() == ocspRevoked {
+			tmpl.Status = ocsp.Revoked
+			tmpl.RevokedAt = time.Now()
+		}
+		resp, _ := ocsp.CreateResponse(ca, ca, tmpl, caKey)
+		w.Write(resp)
+	}))
+	defer ocspServer.Close()
+
+	newLeaf := func(serial int64, ocspServers ...string) *x509.Certificate {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: fmt.Sprintf("client%d", serial)},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			OCSPServer:   ocspServers,
+		}
+		der, _ := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		cert, _ := x509.ParseCertificate(der)
+		return cert
+	}
+	writeCRL := func(serials ...int64) {
+		var revoked []pkix.RevokedCertificate
+		for _, serial := range serials {
+			revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: big.NewInt(serial), RevocationTime: time.Now()})
+		}
+		der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:              big.NewInt(int64(len(serials))),
+			ThisUpdate:          time.Now(),
+			NextUpdate:          time.Now().Add(time.Hour),
+			RevokedCertificates: revoked,
+		}, ca, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+		if err = ioutil.WriteFile(filepath.Join(dir, "ca.crl"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	verify := func(c *CertRevocationChecker, leaf *x509.Certificate) error {
+		return c.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, ca}})
+	}
 
-func (x *MockSetPackageServer) Recv() (*gnoi_system_pb.SetPackageRequest, error) {
-	return nil, nil
-}
+	writeCRL(2)
+	checker, err := NewCertRevocationChecker(filepath.Join(dir, "ca.crl"), false)
+	if err != nil {
+		t.Fatalf("NewCertRevocationChecker failed: %v", err)
+	}
+	if err = verify(checker, newLeaf(1)); err != nil {
+		t.Errorf("Valid certificate rejected: %v", err)
+	}
+	if err = verify(checker, newLeaf(2)); err == nil {
+		t.Errorf("Revoked certificate accepted")
+	}
 
-func TestGnoiAuthorization(t *testing.T) {
-	s := createServer(t, 8081)
-	go runServer(t, s)
-	mockAuthenticate := gomonkey.ApplyFunc(s.Authenticate, func(ctx context.Context, req *spb_jwt.AuthenticateRequest) (*spb_jwt.AuthenticateResponse, error) {
-		return nil, nil
-	})
-	defer mockAuthenticate.Reset()
+	// Reloaded CRL applies to the next verifications
+	writeCRL(1, 2)
+	if err = checker.ReloadCRL(); err != nil {
+		t.Fatalf("ReloadCRL failed: %v", err)
+	}
+	if err = verify(checker, newLeaf(1)); err == nil {
+		t.Errorf("Certificate revoked after reload accepted")
+	}
 
-	err := s.Ping(new(gnoi_system_pb.PingRequest), new(MockPingServer))
-	if err == nil {
-		t.Errorf("Ping should failed, because not implement.")
+	// Invalid CRL keeps the loaded one
+	ioutil.WriteFile(filepath.Join(dir, "ca.crl"), []byte("garbage"), 0644)
+	if err = checker.ReloadCRL(); err == nil {
+		t.Errorf("ReloadCRL of an invalid file should fail")
+	}
+	if err = verify(checker, newLeaf(2)); err == nil {
+		t.Errorf("Revoked certificate accepted after failed reload")
 	}
 
-	s.Traceroute(new(gnoi_system_pb.TracerouteRequest), new(MockTracerouteServer))
-	if err == nil {
-		t.Errorf("Traceroute should failed, because not implement.")
+	ocspChecker, err := NewCertRevocationChecker("", true)
+	if err != nil {
+		t.Fatalf("NewCertRevocationChecker failed: %v", err)
+	}
+	if err = verify(ocspChecker, newLeaf(4, ocspServer.URL)); err != nil {
+		t.Errorf("Certificate good for OCSP rejected: %v", err)
 	}
+	if err = verify(ocspChecker, newLeaf(ocspRevoked, ocspServer.URL)); err == nil {
+		t.Errorf("Certificate revoked by OCSP accepted")
+	}
+	unreachable := newLeaf(5, "http://127.0.0.1:1")
+	if err = verify(ocspChecker, unreachable); err == nil {
+		t.Errorf("Certificate without OCSP status accepted")
+	}
+	ocspChecker.OcspSoftFail = true
+	if err = verify(ocspChecker, unreachable); err != nil {
+		t.Errorf("Certificate without OCSP status rejected in soft fail mode: %v", err)
+	}
+}
 
-	s.SetPackage(new(MockSetPackageServer))
-	if err == nil {
-		t.Errorf("SetPackage should failed, because not implement.")
+// allowAllAuthorizer stands for an authorization policy allowing everything.
+type allowAllAuthorizer struct{}
+
+func (allowAllAuthorizer) Authorize(auth *common_utils.AuthInfo, req *AuthzRequest) error {
+	return nil
+}
+
+func TestGnoiFile(t *testing.T) {
+	root := t.TempDir()
+	allowed := filepath.Join(root, "allowed")
+	denied := filepath.Join(root, "denied")
+	for _, dir := range []string{allowed, denied} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Mkdir failed: %v", err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(denied, "secret"), []byte("secret"), 0600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(denied, "secret"), filepath.Join(allowed, "link")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
 	}
 
-	ctx := context.Background()
-	s.SwitchControlProcessor(ctx, new(gnoi_system_pb.SwitchControlProcessorRequest))
-	if err == nil {
-		t.Errorf("SwitchControlProcessor should failed, because not implement.")
+	certificate, err := testcert.NewCert()
+	if err != nil {
+		t.Fatalf("could not load server key pair: %s", err)
+	}
+	tlsCfg := &tls.Config{ClientAuth: tls.RequestClientCert, Certificates: []tls.Certificate{certificate}}
+	// The File service is only served with an authorization policy and user authentication
+	cfg := &Config{Port: 8081, EnableTranslibWrite: true, EnableNativeWrite: true, Threshold: 100, FileDirs: []string{allowed}}
+	if _, err = NewServer(cfg, []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}); err == nil {
+		t.Fatalf("NewServer should fail without authorization policy")
+	}
+	cfg.Authorizer = allowAllAuthorizer{}
+	if _, err = NewServer(cfg, []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))}); err == nil {
+		t.Fatalf("NewServer should fail without user authentication")
 	}
 
-	s.Refresh(ctx, new(spb_jwt.RefreshRequest))
-	if err == nil {
-		t.Errorf("Refresh should failed, because not implement.")
+	cfg.UserAuth = AuthTypes{"password": true}
+	mock := gomonkey.ApplyFunc(BasicAuthenAndAuthor, func(ctx context.Context) (context.Context, error) {
+		return ctx, nil
+	})
+	defer mock.Reset()
+	s, err := NewServer(cfg, []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsCfg))})
+	if err != nil {
+		t.Fatalf("Failed to create gNMI server: %v", err)
 	}
+	go runServer(t, s)
+	defer s.Stop()
 
-	s.ClearNeighbors(ctx, new(sgpb.ClearNeighborsRequest))
-	if err == nil {
-		t.Errorf("ClearNeighbors should failed, because not implement.")
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.Dial("127.0.0.1:8081", grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		t.Fatalf("Dialing failed: %v", err)
 	}
+	defer conn.Close()
+	fc := gnoi_file_pb.NewFileClient(conn)
 
-	s.CopyConfig(ctx, new(sgpb.CopyConfigRequest))
-	if err == nil {
-		t.Errorf("CopyConfig should failed, because not implement.")
+	put := func(name string, data []byte, hash []byte) error {
+		stream, err := fc.Put(ctx)
+		if err != nil {
+			return err
+		}
+		reqs := []*gnoi_file_pb.PutRequest{
+			{Request: &gnoi_file_pb.PutRequest_Open{Open: &gnoi_file_pb.PutRequest_Details{RemoteFile: name, Permissions: 640}}},
+			{Request: &gnoi_file_pb.PutRequest_Contents{Contents: data[:len(data)/2]}},
+			{Request: &gnoi_file_pb.PutRequest_Contents{Contents: data[len(data)/2:]}},
+			{Request: &gnoi_file_pb.PutRequest_Hash{Hash: &gnoi_types_pb.HashType{Method: gnoi_types_pb.HashType_SHA256, Hash: hash}}},
+		}
+		for _, req := range reqs {
+			if err = stream.Send(req); err != nil {
+				break
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
 	}
 
-	s.Stop()
-}
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	sum := sha256.Sum256(data)
+	name := filepath.Join(allowed, "image.bin")
+	if err = put(name, data, sum[:]); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if fi, err := os.Stat(name); err != nil || fi.Mode().Perm() != 0640 {
+		t.Errorf("Put file %v has wrong mode: %v", fi, err)
+	}
+
+	if err = put(filepath.Join(allowed, "corrupt.bin"), data, []byte("bad")); status.Code(err) != codes.DataLoss {
+		t.Errorf("Put with wrong hash should fail with DataLoss: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(allowed, "corrupt.bin")); !os.IsNotExist(err) {
+		t.Errorf("Corrupt file should not be written: %v", err)
+	}
+	if err = put(filepath.Join(denied, "image.bin"), data, sum[:]); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Put outside allowed directories should be denied: %v", err)
+	}
+	defer func(size int64) { maxFilePutSize = size }(maxFilePutSize)
+	maxFilePutSize = int64(len(data) - 1)
+	if err = put(filepath.Join(allowed, "large.bin"), data, sum[:]); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Put beyond the maximum size should fail with ResourceExhausted: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(allowed, "large.bin")); !os.IsNotExist(err) {
+		t.Errorf("Too large file should not be written: %v", err)
+	}
+
+	get, err := fc.Get(ctx, &gnoi_file_pb.GetRequest{RemoteFile: name})
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	var got []byte
+	var gotHash []byte
+	for {
+		resp, err := get.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		got = append(got, resp.GetContents()...)
+		if resp.GetHash() != nil {
+			gotHash = resp.GetHash().GetHash()
+		}
+	}
+	if !bytes.Equal(got, data) || !bytes.Equal(gotHash, sum[:]) {
+		t.Errorf("Get returned %d bytes with hash %x", len(got), gotHash)
+	}
+
+	for _, path := range []string{filepath.Join(allowed, "link"), allowed + "/../denied/secret", "relative"} {
+		get, err = fc.Get(ctx, &gnoi_file_pb.GetRequest{RemoteFile: path})
+		if err == nil {
+			_, err = get.Recv()
+		}
+		if code := status.Code(err); code != codes.PermissionDenied && code != codes.InvalidArgument {
+			t.Errorf("Get of %v should be denied: %v", path, err)
+		}
+	}
+
+	stat, err := fc.Stat(ctx, &gnoi_file_pb.StatRequest{Path: allowed})
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	found := false
+	for _, info := range stat.GetStats() {
+		if info.GetPath() == name {
+			found = info.GetSize() == uint64(len(data)) && info.GetPermissions() == 640
+		}
+	}
+	if !found {
+		t.Errorf("Stat of %v does not describe %v: %v", allowed, name, stat)
+	}
+
+	if _, err = fc.Remove(ctx, &gnoi_file_pb.RemoveRequest{RemoteFile: allowed}); status.Code(err) != codes.PermissionDenied && status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Remove of directory should fail: %v", err)
+	}
+	if _, err = fc.Remove(ctx, &gnoi_file_pb.RemoveRequest{RemoteFile: name}); err != nil {
+		t.Errorf("Remove failed: %v", err)
+	}
+	if _, err = fc.Stat(ctx, &gnoi_file_pb.StatRequest{Path: name}); status.Code(err) != codes.NotFound {
+		t.Errorf("Stat of removed file should fail with NotFound: %v", err)
+	}
+
+	// Roles are limited to their own directories
+	rp := &RolePolicy{Rpcs: []string{"/gnoi.file.File/*"}, FileRead: []string{"/var/log"}, FileWrite: []string{"/tmp/images/"}}
+	fileReqs := []struct {
+		req  *AuthzRequest
+		want bool
+	}{
+		{&AuthzRequest{Rpc: rpcFileGet, Files: []string{"/var/log/syslog"}}, true},
+		{&AuthzRequest{Rpc: rpcFileGet, Files: []string{"/var/logs"}}, false},
+		{&AuthzRequest{Rpc: rpcFilePut, Write: true, Files: []string{"/var/log/syslog"}}, false},
+		{&AuthzRequest{Rpc: rpcFilePut, Write: true, Files: []string{"/tmp/images/sonic.bin"}}, true},
+	}
+	for _, fr := range fileReqs {
+		if got := rp.allows(fr.req); got != fr.want {
+			t.Errorf("allows(%v %v) = %v, want %v", fr.req.Rpc, fr.req.Files, got, fr.want)
+		}
+	}
+}
+
+func TestGnoiOs(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	// The host sees the image directory at another path
+	s.config.ImageDir = t.TempDir()
+	s.config.HostImageDir = "/host/images"
+
+	images := &sonicImages{Current: "SONiC-OS-1.0", Next: "SONiC-OS-1.0", Available: []string{"SONiC-OS-1.0"}}
+	var installed []byte
+	var nextBoot string
+	mockList := gomonkey.ApplyMethod(reflect.TypeOf(&ssc.DbusClient{}), "ListImages", func(_ *ssc.DbusClient) (string, error) {
+		data, err := json.Marshal(images)
+		return string(data), err
+	})
+	defer mockList.Reset()
+	mockInstall := gomonkey.ApplyMethod(reflect.TypeOf(&ssc.DbusClient{}), "InstallImage", func(_ *ssc.DbusClient, fileName string) error {
+		if filepath.Dir(fileName) != "/host/images" {
+			return fmt.Errorf("image %v is not in the host image directory", fileName)
+		}
+		var err error
+		installed, err = ioutil.ReadFile(filepath.Join(s.config.ImageDir, filepath.Base(fileName)))
+		images.Available = append(images.Available, "SONiC-OS-2.0")
+		return err
+	})
+	defer mockInstall.Reset()
+	mockNextBoot := gomonkey.ApplyMethod(reflect.TypeOf(&ssc.DbusClient{}), "SetNextBoot", func(_ *ssc.DbusClient, image string) error {
+		nextBoot = image
+		return nil
+	})
+	defer mockNextBoot.Reset()
+	savedRebooter := rebooter
+	rebooter = &rebootScheduler{}
+	defer func() { rebooter = savedRebooter }()
+	rebooted := make(chan gnoi_system_pb.RebootMethod, 1)
+	mockIssue := gomonkey.ApplyFunc(issueReboot, func(method gnoi_system_pb.RebootMethod, message string) error {
+		rebooted <- method
+		return nil
+	})
+	defer mockIssue.Reset()
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	conn, err := grpc.Dial("127.0.0.1:8081", grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	if err != nil {
+		t.Fatalf("Dialing failed: %v", err)
+	}
+	defer conn.Close()
+	oc := gnoi_os_pb.NewOSClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	verify, err := oc.Verify(ctx, &gnoi_os_pb.VerifyRequest{})
+	if err != nil || verify.GetVersion() != "SONiC-OS-1.0" {
+		t.Errorf("Verify returned %v, %v", verify, err)
+	}
+
+	// install sends the image, if requested, and returns all the responses
+	install := func(ctx context.Context, version string, image []byte) []*gnoi_os_pb.InstallResponse {
+		stream, err := oc.Install(ctx)
+		if err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		err = stream.Send(&gnoi_os_pb.InstallRequest{Request: &gnoi_os_pb.InstallRequest_TransferRequest{
+			TransferRequest: &gnoi_os_pb.TransferRequest{Version: version},
+		}})
+		if err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Install failed: %v", err)
+		}
+		resps := []*gnoi_os_pb.InstallResponse{resp}
+		if resp.GetTransferReady() == nil {
+			return resps
+		}
+		for len(image) > 0 {
+			n := 64 * 1024
+			if n > len(image) {
+				n = len(image)
+			}
+			stream.Send(&gnoi_os_pb.InstallRequest{Request: &gnoi_os_pb.InstallRequest_TransferContent{TransferContent: image[:n]}})
+			image = image[n:]
+		}
+		stream.Send(&gnoi_os_pb.InstallRequest{Request: &gnoi_os_pb.InstallRequest_TransferEnd{TransferEnd: &gnoi_os_pb.TransferEnd{}}})
+		stream.CloseSend()
+		for {
+			resp, err = stream.Recv()
+			if err == io.EOF {
+				return resps
+			}
+			if err != nil {
+				t.Fatalf("Install failed: %v", err)
+			}
+			resps = append(resps, resp)
+		}
+	}
+
+	resps := install(ctx, "SONiC-OS-1.0", nil)
+	if len(resps) != 1 || resps[0].GetValidated().GetVersion() != "SONiC-OS-1.0" {
+		t.Errorf("Install of installed version should be validated at once: %v", resps)
+	}
+
+	image := bytes.Repeat([]byte("sonic"), 500*1024)
+	sum := sha256.Sum256(image)
+	badCtx := metadata.AppendToOutgoingContext(ctx, "image-sha256", "00")
+	resps = install(badCtx, "", image)
+	if last := resps[len(resps)-1]; last.GetInstallError().GetType() != gnoi_os_pb.InstallError_INTEGRITY_FAIL {
+		t.Errorf("Install with wrong hash should fail integrity check: %v", last)
+	}
+	if installed != nil {
+		t.Errorf("Image with wrong hash should not be installed")
+	}
+
+	hashCtx := metadata.AppendToOutgoingContext(ctx, "image-sha256", hex.EncodeToString(sum[:]))
+	resps = install(hashCtx, "", image)
+	progress := 0
+	for _, resp := range resps {
+		if resp.GetTransferProgress() != nil {
+			progress++
+		}
+	}
+	if progress != 2 {
+		t.Errorf("Got %d progress responses, want 2", progress)
+	}
+	if last := resps[len(resps)-1]; last.GetValidated().GetVersion() != "SONiC-OS-2.0" {
+		t.Errorf("Install should validate the new version: %v", last)
+	}
+	if !bytes.Equal(installed, image) {
+		t.Errorf("Installed image of %d bytes differs from the sent one", len(installed))
+	}
+
+	// Without hash the image is installed unverified, unless the hash is required
+	images.Available = []string{"SONiC-OS-1.0"}
+	installed = nil
+	resps = install(ctx, "", image)
+	if last := resps[len(resps)-1]; last.GetValidated().GetVersion() != "SONiC-OS-2.0" {
+		t.Errorf("Install without hash should validate the new version: %v", last)
+	}
+	if !bytes.Equal(installed, image) {
+		t.Errorf("Installed image of %d bytes differs from the sent one", len(installed))
+	}
+	s.config.RequireImageHash = true
+	stream, err := oc.Install(ctx)
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	stream.Send(&gnoi_os_pb.InstallRequest{Request: &gnoi_os_pb.InstallRequest_TransferRequest{
+		TransferRequest: &gnoi_os_pb.TransferRequest{},
+	}})
+	if resp, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Install without required hash returned %v, %v", resp, err)
+	}
+	s.config.RequireImageHash = false
+
+	images.Available = []string{"SONiC-OS-1.0"}
+	installed = nil
+	s.config.MaxImageSize = int64(len(image) - 1)
+	resps = install(ctx, "", image)
+	if last := resps[len(resps)-1]; last.GetInstallError().GetType() != gnoi_os_pb.InstallError_TOO_LARGE {
+		t.Errorf("Install beyond the maximum size should fail with TOO_LARGE: %v", last)
+	}
+	if installed != nil {
+		t.Errorf("Too large image should not be installed")
+	}
+	s.config.MaxImageSize = 0
+
+	s.config.ImageDir = ""
+	stream, err = oc.Install(ctx)
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	stream.Send(&gnoi_os_pb.InstallRequest{Request: &gnoi_os_pb.InstallRequest_TransferRequest{
+		TransferRequest: &gnoi_os_pb.TransferRequest{},
+	}})
+	if resp, err := stream.Recv(); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Install without image directory returned %v, %v", resp, err)
+	}
+	images.Available = []string{"SONiC-OS-1.0", "SONiC-OS-2.0"}
+
+	activate, err := oc.Activate(ctx, &gnoi_os_pb.ActivateRequest{Version: "SONiC-OS-3.0"})
+	if err != nil || activate.GetActivateError().GetType() != gnoi_os_pb.ActivateError_NON_EXISTENT_VERSION {
+		t.Errorf("Activate of unknown version returned %v, %v", activate, err)
+	}
+	activate, err = oc.Activate(ctx, &gnoi_os_pb.ActivateRequest{Version: "SONiC-OS-2.0", NoReboot: true})
+	if err != nil || activate.GetActivateOk() == nil || nextBoot != "SONiC-OS-2.0" {
+		t.Errorf("Activate returned %v, %v, next boot %v", activate, err, nextBoot)
+	}
+	select {
+	case method := <-rebooted:
+		t.Errorf("Activate with no_reboot rebooted with method %v", method)
+	default:
+	}
+	nextBoot = ""
+	activate, err = oc.Activate(ctx, &gnoi_os_pb.ActivateRequest{Version: "SONiC-OS-2.0"})
+	if err != nil || activate.GetActivateOk() == nil || nextBoot != "SONiC-OS-2.0" {
+		t.Errorf("Activate returned %v, %v, next boot %v", activate, err, nextBoot)
+	}
+	select {
+	case method := <-rebooted:
+		if method != gnoi_system_pb.RebootMethod_COLD {
+			t.Errorf("Activate rebooted with method %v", method)
+		}
+	default:
+		t.Errorf("Activate did not reboot")
+	}
+}
+
+type MockServerStream struct {
+	grpc.ServerStream
+}
+
+func (x *MockServerStream) Context() context.Context {
+	return context.Background()
+}
+
+type MockPingServer struct {
+	MockServerStream
+}
+
+func (x *MockPingServer) Send(m *gnoi_system_pb.PingResponse) error {
+	return nil
+}
+
+type MockTracerouteServer struct {
+	MockServerStream
+}
+
+func (x *MockTracerouteServer) Send(m *gnoi_system_pb.TracerouteResponse) error {
+	return nil
+}
+
+type MockSetPackageServer struct {
+	MockServerStream
+}
+
+func (x *MockSetPackageServer) Send(m *gnoi_system_pb.SetPackageResponse) error {
+	return nil
+}
+
+func (x *MockSetPackageServer) SendAndClose(m *gnoi_system_pb.SetPackageResponse) error {
+	return nil
+}
+
+func (x *MockSetPackageServer) Recv() (*gnoi_system_pb.SetPackageRequest, error) {
+	return nil, nil
+}
+
+func TestGnoiAuthorization(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	mockAuthenticate := gomonkey.ApplyFunc(s.Authenticate, func(ctx context.Context, req *spb_jwt.AuthenticateRequest) (*spb_jwt.AuthenticateResponse, error) {
+		return nil, nil
+	})
+	defer mockAuthenticate.Reset()
+
+	err := s.Ping(new(gnoi_system_pb.PingRequest), new(MockPingServer))
+	if err == nil {
+		t.Errorf("Ping should failed, because not implement.")
+	}
+
+	s.Traceroute(new(gnoi_system_pb.TracerouteRequest), new(MockTracerouteServer))
+	if err == nil {
+		t.Errorf("Traceroute should failed, because not implement.")
+	}
+
+	s.SetPackage(new(MockSetPackageServer))
+	if err == nil {
+		t.Errorf("SetPackage should failed, because not implement.")
+	}
+
+	ctx := context.Background()
+	s.SwitchControlProcessor(ctx, new(gnoi_system_pb.SwitchControlProcessorRequest))
+	if err == nil {
+		t.Errorf("SwitchControlProcessor should failed, because not implement.")
+	}
+
+	s.Refresh(ctx, new(spb_jwt.RefreshRequest))
+	if err == nil {
+		t.Errorf("Refresh should failed, because not implement.")
+	}
+
+	s.ClearNeighbors(ctx, new(sgpb.ClearNeighborsRequest))
+	if err == nil {
+		t.Errorf("ClearNeighbors should failed, because not implement.")
+	}
+
+	s.CopyConfig(ctx, new(sgpb.CopyConfigRequest))
+	if err == nil {
+		t.Errorf("CopyConfig should failed, because not implement.")
+	}
+
+	s.Stop()
+}
+
+func TestGnoiReboot(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	issued := make(chan gnoi_system_pb.RebootMethod, 1)
+	mockIssue := gomonkey.ApplyFunc(issueReboot, func(method gnoi_system_pb.RebootMethod, message string) error {
+		issued <- method
+		return nil
+	})
+	defer mockIssue.Reset()
+
+	ctx := context.Background()
+	_, err := s.Reboot(ctx, &gnoi_system_pb.RebootRequest{Method: gnoi_system_pb.RebootMethod_HALT})
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Reboot with HALT method should be unimplemented: %v", err)
+	}
+
+	_, err = s.Reboot(ctx, &gnoi_system_pb.RebootRequest{Method: gnoi_system_pb.RebootMethod_COLD, Delay: math.MaxInt64 + 1})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Reboot with a delay out of range should be rejected: %v", err)
+	}
+
+	_, err = s.Reboot(ctx, &gnoi_system_pb.RebootRequest{Method: gnoi_system_pb.RebootMethod_COLD})
+	if err != nil {
+		t.Fatalf("Reboot failed: %v", err)
+	}
+	if method := <-issued; method != gnoi_system_pb.RebootMethod_COLD {
+		t.Errorf("Reboot issued with method %v", method)
+	}
+	resp, err := s.RebootStatus(ctx, &gnoi_system_pb.RebootStatusRequest{})
+	if err != nil {
+		t.Fatalf("RebootStatus failed: %v", err)
+	}
+	if resp.Active || resp.Count != 1 {
+		t.Errorf("Unexpected status after reboot: %v", resp)
+	}
+
+	req := &gnoi_system_pb.RebootRequest{
+		Method:  gnoi_system_pb.RebootMethod_WARM,
+		Delay:   uint64(time.Hour),
+		Message: "maintenance",
+	}
+	if _, err = s.Reboot(ctx, req); err != nil {
+		t.Fatalf("Delayed reboot failed: %v", err)
+	}
+	if _, err = s.Reboot(ctx, req); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Second reboot should fail while pending: %v", err)
+	}
+	resp, err = s.RebootStatus(ctx, &gnoi_system_pb.RebootStatusRequest{})
+	if err != nil {
+		t.Fatalf("RebootStatus failed: %v", err)
+	}
+	if !resp.Active || resp.Reason != "maintenance" || resp.Wait == 0 || resp.Wait > uint64(time.Hour) {
+		t.Errorf("Unexpected status of pending reboot: %v", resp)
+	}
+
+	if _, err = s.CancelReboot(ctx, &gnoi_system_pb.CancelRebootRequest{Message: "done"}); err != nil {
+		t.Fatalf("CancelReboot failed: %v", err)
+	}
+	resp, err = s.RebootStatus(ctx, &gnoi_system_pb.RebootStatusRequest{})
+	if err != nil {
+		t.Fatalf("RebootStatus failed: %v", err)
+	}
+	if resp.Active || resp.Count != 1 {
+		t.Errorf("Unexpected status after cancel: %v", resp)
+	}
+	select {
+	case method := <-issued:
+		t.Errorf("Cancelled reboot issued with method %v", method)
+	default:
+	}
+}
+
+func TestGnoiKillProcess(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	var methods []string
+	var options []string
+	mock1 := gomonkey.ApplyFunc(dbus.SystemBus, func() (conn *dbus.Conn, err error) {
+		return &dbus.Conn{}, nil
+	})
+	defer mock1.Reset()
+	mock2 := gomonkey.ApplyMethod(reflect.TypeOf(&dbus.Object{}), "Go", func(obj *dbus.Object, method string, flags dbus.Flags, ch chan *dbus.Call, args ...interface{}) *dbus.Call {
+		methods = append(methods, method)
+		options = append(options, fmt.Sprint(args...))
+		ret := &dbus.Call{}
+		ret.Err = nil
+		ret.Body = make([]interface{}, 2)
+		ret.Body[0] = int32(0)
+		ch <- ret
+		return &dbus.Call{}
+	})
+	defer mock2.Reset()
+
+	tds := []struct {
+		desc        string
+		req         *gnoi_system_pb.KillProcessRequest
+		wantRetCode codes.Code
+		wantMethod  string
+		wantOptions string
+	}{
+		{
+			desc:        "stop service",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "snmp", Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_TERM},
+			wantRetCode: codes.OK,
+			wantMethod:  "org.SONiC.HostService.systemd.stop_service",
+			wantOptions: "snmp",
+		},
+		{
+			desc:        "hup process of container",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "bgp", Pid: 42, Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_HUP},
+			wantRetCode: codes.OK,
+			wantMethod:  "org.SONiC.HostService.process.kill_process",
+			wantOptions: `{"container":"bgp","pid":42,"signal":1}`,
+		},
+		{
+			desc:        "kill main process of container",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "snmp", Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_KILL},
+			wantRetCode: codes.OK,
+			wantMethod:  "org.SONiC.HostService.process.kill_process",
+			wantOptions: `{"container":"snmp","pid":0,"signal":9}`,
+		},
+		{
+			desc:        "abort process of container",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "swss", Pid: 1234, Signal: killProcessSignalAbrt},
+			wantRetCode: codes.OK,
+			wantMethod:  "org.SONiC.HostService.process.kill_process",
+			wantOptions: `{"container":"swss","pid":1234,"signal":6}`,
+		},
+		{
+			desc:        "host process",
+			req:         &gnoi_system_pb.KillProcessRequest{Pid: 1234, Signal: killProcessSignalAbrt},
+			wantRetCode: codes.InvalidArgument,
+		},
+		{
+			desc:        "init process",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "bgp", Pid: 1, Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_KILL},
+			wantRetCode: codes.InvalidArgument,
+		},
+		{
+			desc:        "unspecified signal",
+			req:         &gnoi_system_pb.KillProcessRequest{Name: "snmp"},
+			wantRetCode: codes.InvalidArgument,
+		},
+		{
+			desc:        "no name nor pid",
+			req:         &gnoi_system_pb.KillProcessRequest{Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_HUP},
+			wantRetCode: codes.InvalidArgument,
+		},
+		{
+			desc:        "restart process by pid",
+			req:         &gnoi_system_pb.KillProcessRequest{Pid: 42, Restart: true, Signal: gnoi_system_pb.KillProcessRequest_SIGNAL_TERM},
+			wantRetCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			methods, options = nil, nil
+			_, err := s.KillProcess(context.Background(), td.req)
+			if status.Code(err) != td.wantRetCode {
+				t.Fatalf("Got error %v, want code %v", err, td.wantRetCode)
+			}
+			if td.wantMethod == "" {
+				if len(methods) != 0 {
+					t.Errorf("DBUS API invoked unexpectedly: %v", methods)
+				}
+				return
+			}
+			if len(methods) != 1 || methods[0] != td.wantMethod || options[0] != td.wantOptions {
+				t.Errorf("Got DBUS calls %v %v, want %v %v", methods, options, td.wantMethod, td.wantOptions)
+			}
+		})
+	}
+}
+
+type fakeCommandRunner struct {
+	name   string
+	args   []string
+	output string
+	err    error
+}
+
+func (r *fakeCommandRunner) Run(ctx context.Context, name string, args []string, fn func(line string) error) error {
+	r.name, r.args = name, args
+	for _, line := range strings.Split(r.output, "\n") {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+type collectPingServer struct {
+	MockServerStream
+	ctx   context.Context
+	resps []*gnoi_system_pb.PingResponse
+}
+
+func (x *collectPingServer) Context() context.Context {
+	return x.ctx
+}
+
+func (x *collectPingServer) Send(m *gnoi_system_pb.PingResponse) error {
+	x.resps = append(x.resps, m)
+	return nil
+}
+
+type collectTracerouteServer struct {
+	MockServerStream
+	ctx   context.Context
+	resps []*gnoi_system_pb.TracerouteResponse
+}
+
+func (x *collectTracerouteServer) Context() context.Context {
+	return x.ctx
+}
+
+func (x *collectTracerouteServer) Send(m *gnoi_system_pb.TracerouteResponse) error {
+	x.resps = append(x.resps, m)
+	return nil
+}
+
+func TestGnoiPingTraceroute(t *testing.T) {
+	s := createServer(t, 8081)
+	go runServer(t, s)
+	defer s.Stop()
+
+	runner := &fakeCommandRunner{}
+	savedRunner := diagRunner
+	diagRunner = runner
+	defer func() { diagRunner = savedRunner }()
+
+	runner.output = `PING 10.0.0.1 (10.0.0.1) 100(128) bytes of data.
+108 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=0.512 ms
+108 bytes from 10.0.0.1: icmp_seq=2 ttl=64 time=1.50 ms
+
+--- 10.0.0.1 ping statistics ---
+2 packets transmitted, 2 received, 0% packet loss, time 1001ms
+rtt min/avg/max/mdev = 0.512/1.006/1.500/0.494 ms`
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("network-namespace", "asic0", "vrf", "Vrf-red"))
+	ps := &collectPingServer{ctx: ctx}
+	err := s.Ping(&gnoi_system_pb.PingRequest{
+		Destination:   "10.0.0.1",
+		Source:        "10.0.0.2",
+		Count:         2,
+		Interval:      int64(500 * time.Millisecond),
+		Size:          100,
+		DoNotFragment: true,
+	}, ps)
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	wantArgs := []string{"netns", "exec", "asic0", "ip", "vrf", "exec", "Vrf-red", "ping",
+		"-c", "2", "-i", "0.5", "-s", "100", "-M", "do", "-I", "10.0.0.2", "10.0.0.1"}
+	if runner.name != "ip" || !reflect.DeepEqual(runner.args, wantArgs) {
+		t.Errorf("Wrong ping command: %v %v", runner.name, runner.args)
+	}
+	wantPing := []*gnoi_system_pb.PingResponse{
+		{Source: "10.0.0.1", Time: 512000, Bytes: 108, Sequence: 1, Ttl: 64},
+		{Source: "10.0.0.1", Time: 1500000, Bytes: 108, Sequence: 2, Ttl: 64},
+		{Source: "10.0.0.1", Time: 1001000000, Sent: 2, Received: 2,
+			MinTime: 512000, AvgTime: 1006000, MaxTime: 1500000, StdDev: 494000},
+	}
+	if len(ps.resps) != len(wantPing) {
+		t.Fatalf("Got %d ping responses, want %d", len(ps.resps), len(wantPing))
+	}
+	for i := range wantPing {
+		if !proto.Equal(ps.resps[i], wantPing[i]) {
+			t.Errorf("Ping response %d: got %v, want %v", i, ps.resps[i], wantPing[i])
+		}
+	}
+
+	// Statistics are reported when ping fails for lack of replies
+	runner.output = `--- 10.0.0.3 ping statistics ---
+5 packets transmitted, 0 received, 100% packet loss, time 4096ms`
+	runner.err = fmt.Errorf("exit status 1")
+	ps = &collectPingServer{ctx: context.Background()}
+	if err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "10.0.0.3"}, ps); err != nil {
+		t.Fatalf("Ping without replies failed: %v", err)
+	}
+	if len(ps.resps) != 1 || ps.resps[0].Sent != 5 || ps.resps[0].Received != 0 {
+		t.Errorf("Unexpected responses of ping without replies: %v", ps.resps)
+	}
+
+	runner.output = "ping: unknown host"
+	runner.err = fmt.Errorf("exit status 2")
+	err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "nowhere"}, &collectPingServer{ctx: context.Background()})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("Ping of unknown host should fail: %v", err)
+	}
+
+	// A count of -1 pings until cancelled, an interval of -1 floods
+	runner.output = ""
+	runner.err = nil
+	err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "10.0.0.1", Count: -1, Interval: -1},
+		&collectPingServer{ctx: context.Background()})
+	if err != nil {
+		t.Fatalf("Continuous flood ping failed: %v", err)
+	}
+	if wantArgs = []string{"-f", "10.0.0.1"}; runner.name != "ping" || !reflect.DeepEqual(runner.args, wantArgs) {
+		t.Errorf("Wrong continuous flood ping command: %v %v", runner.name, runner.args)
+	}
+	// which read-only roles may not do
+	readOnly := &RolePolicy{Rpcs: []string{rpcSystemPing}, Read: []string{"/"}}
+	for _, req := range []*gnoi_system_pb.PingRequest{
+		{Destination: "10.0.0.1", Count: -1},
+		{Destination: "10.0.0.1", Interval: -1},
+	} {
+		if readOnly.allows(pingAuthzRequest(req)) {
+			t.Errorf("Read-only role should not be allowed ping %v", req)
+		}
+	}
+	if !readOnly.allows(pingAuthzRequest(&gnoi_system_pb.PingRequest{Destination: "10.0.0.1", Count: 5})) {
+		t.Errorf("Read-only role should be allowed ping with count")
+	}
+	err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "10.0.0.1", Count: -2}, &collectPingServer{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Ping with count -2 should fail: %v", err)
+	}
+
+	err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "-fs10.0.0.1"}, &collectPingServer{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Ping of destination starting with - should fail: %v", err)
+	}
+	err = s.Traceroute(&gnoi_system_pb.TracerouteRequest{Destination: "--help"}, &collectTracerouteServer{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Traceroute of destination starting with - should fail: %v", err)
+	}
+
+	badCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("vrf", "red; reboot"))
+	err = s.Ping(&gnoi_system_pb.PingRequest{Destination: "10.0.0.1"}, &collectPingServer{ctx: badCtx})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Ping in invalid VRF should fail: %v", err)
+	}
+
+	runner.output = `traceroute to 10.1.1.1 (10.1.1.1), 5 hops max, 60 byte packets
+ 1  gw.lab (10.0.0.254)  0.350 ms  0.300 ms
+ 2  *  10.0.1.1  1.250 ms !H`
+	runner.err = nil
+	ts := &collectTracerouteServer{ctx: context.Background()}
+	err = s.Traceroute(&gnoi_system_pb.TracerouteRequest{
+		Destination:  "10.1.1.1",
+		MaxTtl:       5,
+		Wait:         int64(2 * time.Second),
+		DoNotResolve: true,
+		L4Protocol:   gnoi_system_pb.TracerouteRequest_UDP,
+	}, ts)
+	if err != nil {
+		t.Fatalf("Traceroute failed: %v", err)
+	}
+	wantArgs = []string{"-U", "-m", "5", "-w", "2", "-n", "10.1.1.1"}
+	if runner.name != "traceroute" || !reflect.DeepEqual(runner.args, wantArgs) {
+		t.Errorf("Wrong traceroute command: %v %v", runner.name, runner.args)
+	}
+	wantTrace := []*gnoi_system_pb.TracerouteResponse{
+		{DestinationName: "10.1.1.1", DestinationAddress: "10.1.1.1", Hops: 5, PacketSize: 60},
+		{Hop: 1, Name: "gw.lab", Address: "10.0.0.254", Rtt: 350000},
+		{Hop: 1, Name: "gw.lab", Address: "10.0.0.254", Rtt: 300000},
+		{Hop: 2, State: gnoi_system_pb.TracerouteResponse_NONE},
+		{Hop: 2, Address: "10.0.1.1", Rtt: 1250000, State: gnoi_system_pb.TracerouteResponse_HOST_UNREACHABLE},
+	}
+	if len(ts.resps) != len(wantTrace) {
+		t.Fatalf("Got %d traceroute responses, want %d", len(ts.resps), len(wantTrace))
+	}
+	for i := range wantTrace {
+		if !proto.Equal(ts.resps[i], wantTrace[i]) {
+			t.Errorf("Traceroute response %d: got %v, want %v", i, ts.resps[i], wantTrace[i])
+		}
+	}
+}
+
+func TestRoleBasedAuthorization(t *testing.T) {
+	mockPopulate := gomonkey.ApplyFunc(PopulateAuthStruct, func(username string, auth *common_utils.AuthInfo, r []string) error {
+		auth.User = username
+		auth.Roles = []string{username}
+		return nil
+	})
+	defer mockPopulate.Reset()
+	mockPwAuth := gomonkey.ApplyFunc(UserPwAuth, func(username string, passwd string) (bool, error) {
+		return true, nil
+	})
+	defer mockPwAuth.Reset()
+
+	policyFile := filepath.Join(t.TempDir(), "authz.json")
+	policy := `{
+		"roles": {
+			"reader": {
+				"rpcs": ["/gnmi.gNMI/Get", "/gnoi.system.System/*"],
+				"read": ["/COUNTERS_DB"]
+			},
+			"writer": {
+				"rpcs": ["*"],
+				"read": ["/"],
+				"write": ["/"]
+			},
+			"auditor": {
+				"rpcs": ["/gnoi.system.System/*"]
+			}
+		}
+	}`
+	if err := ioutil.WriteFile(policyFile, []byte(policy), 0644); err != nil {
+		t.Fatalf("write file %v err: %v", policyFile, err)
+	}
+	authorizer, err := NewFileAuthorizer(policyFile)
+	if err != nil {
+		t.Fatalf("NewFileAuthorizer failed: %v", err)
+	}
+
+	s := createAuthServer(t, 8081)
+	s.config.Authorizer = authorizer
+	go runServer(t, s)
+	defer s.Stop()
+
+	ns, _ := sdcfg.GetDbDefaultNamespace()
+	prepareDb(t, ns)
+
+	dial := func(username string) *grpc.ClientConn {
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		cred := &loginCreds{Username: username, Password: "dummy"}
+		opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), grpc.WithPerRPCCredentials(cred)}
+		conn, err := grpc.Dial("127.0.0.1:8081", opts...)
+		if err != nil {
+			t.Fatalf("Dialing failed: %v", err)
+		}
+		return conn
+	}
+	readerConn := dial("reader")
+	defer readerConn.Close()
+	writerConn := dial("writer")
+	defer writerConn.Close()
+	guestConn := dial("guest")
+	defer guestConn.Close()
+	auditorConn := dial("auditor")
+	defer auditorConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	getReq := func(target, elem string) *pb.GetRequest {
+		return &pb.GetRequest{
+			Prefix:   &pb.Path{Target: target},
+			Path:     []*pb.Path{{Elem: []*pb.PathElem{{Name: elem}}}},
+			Encoding: pb.Encoding_JSON_IETF,
+		}
+	}
+	setReq := &pb.SetRequest{
+		Prefix: &pb.Path{Target: "CONFIG_DB"},
+		Delete: []*pb.Path{{Elem: []*pb.PathElem{{Name: "PORT"}}}},
+	}
+
+	tds := []struct {
+		desc        string
+		conn        *grpc.ClientConn
+		call        func(conn *grpc.ClientConn) error
+		wantRetCode codes.Code
+	}{
+		{
+			desc: "reader gets allowed path",
+			conn: readerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewGNMIClient(conn).Get(ctx, getReq("COUNTERS_DB", "COUNTERS_PORT_NAME_MAP"))
+				return err
+			},
+			wantRetCode: codes.OK,
+		},
+		{
+			desc: "reader gets denied path",
+			conn: readerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewGNMIClient(conn).Get(ctx, getReq("CONFIG_DB", "PORT"))
+				return err
+			},
+			wantRetCode: codes.PermissionDenied,
+		},
+		{
+			desc: "writer gets any path",
+			conn: writerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewGNMIClient(conn).Get(ctx, getReq("CONFIG_DB", "PORT"))
+				return err
+			},
+			wantRetCode: codes.OK,
+		},
+		{
+			desc: "reader sets path",
+			conn: readerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := pb.NewGNMIClient(conn).Set(ctx, setReq)
+				return err
+			},
+			wantRetCode: codes.PermissionDenied,
+		},
+		{
+			desc: "reader calls read gNOI rpc",
+			conn: readerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := gnoi_system_pb.NewSystemClient(conn).Time(ctx, &gnoi_system_pb.TimeRequest{})
+				return err
+			},
+			wantRetCode: codes.OK,
+		},
+		{
+			desc: "reader calls write gNOI rpc",
+			conn: readerConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := gnoi_system_pb.NewSystemClient(conn).Reboot(ctx, &gnoi_system_pb.RebootRequest{})
+				return err
+			},
+			wantRetCode: codes.PermissionDenied,
+		},
+		{
+			desc: "role without read paths calls read gNOI rpc",
+			conn: auditorConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := gnoi_system_pb.NewSystemClient(conn).Time(ctx, &gnoi_system_pb.TimeRequest{})
+				return err
+			},
+			wantRetCode: codes.PermissionDenied,
+		},
+		{
+			desc: "unknown role calls gNOI rpc",
+			conn: guestConn,
+			call: func(conn *grpc.ClientConn) error {
+				_, err := gnoi_system_pb.NewSystemClient(conn).Time(ctx, &gnoi_system_pb.TimeRequest{})
+				return err
+			},
+			wantRetCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, td := range tds {
+		t.Run(td.desc, func(t *testing.T) {
+			err := td.call(td.conn)
+			if status.Code(err) != td.wantRetCode {
+				t.Fatalf("got return code %v, want %v: %v", status.Code(err), td.wantRetCode, err)
+			}
+		})
+	}
+
+	// Policy changes apply while the server is running
+	stop := make(chan struct{})
+	defer close(stop)
+	if err = authorizer.Watch(stop); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	policy = strings.Replace(policy, `"read": ["/COUNTERS_DB"]`, `"read": ["/COUNTERS_DB", "/CONFIG_DB"]`, 1)
+	if err = ioutil.WriteFile(policyFile, []byte(policy), 0644); err != nil {
+		t.Fatalf("write file %v err: %v", policyFile, err)
+	}
+	for i := 0; ; i++ {
+		_, err = pb.NewGNMIClient(readerConn).Get(ctx, getReq("CONFIG_DB", "PORT"))
+		if err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("Reader still denied after policy change: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Policy elements with keys only match the same key values
+	rp := &RolePolicy{Rpcs: []string{"*"}, Read: []string{"/CONFIG_DB/PORT[name=Ethernet0]", "/CONFIG_DB/VLAN[name=*]/mtu"}}
+	portPath := func(table, name string, elems ...string) *pb.Path {
+		p := &pb.Path{Elem: []*pb.PathElem{{Name: table, Key: map[string]string{"name": name}}}}
+		for _, e := range elems {
+			p.Elem = append(p.Elem, &pb.PathElem{Name: e})
+		}
+		return p
+	}
+	keyReqs := []struct {
+		path *pb.Path
+		want bool
+	}{
+		{portPath("PORT", "Ethernet0", "mtu"), true},
+		{portPath("PORT", "Ethernet4", "mtu"), false},
+		{&pb.Path{Elem: []*pb.PathElem{{Name: "PORT"}}}, false},
+		{portPath("VLAN", "Vlan100", "mtu"), true},
+		{portPath("VLAN", "Vlan100", "members"), false},
+	}
+	for _, kr := range keyReqs {
+		req := &AuthzRequest{Rpc: rpcGnmiGet, Prefix: &pb.Path{Target: "CONFIG_DB"}, Paths: []*pb.Path{kr.path}}
+		if got := rp.allows(req); got != kr.want {
+			t.Errorf("allows(%v) = %v, want %v", kr.path, got, kr.want)
+		}
+	}
+}
 
 func init() {
 	// Enable logs at UT setup
@@ -4395,6 +6240,254 @@ func init() {
 	sdc.UseRedisLocalTcpPort = true
 }
 
+func TestJwtKeyStore(t *testing.T) {
+	origKeys, origValidInt := jwtKeys, JwtValidInt
+	defer func() {
+		SetJwtKeyStore(origKeys)
+		JwtValidInt = origValidInt
+	}()
+	JwtValidInt = time.Hour
+
+	dir, err := ioutil.TempDir("", "jwt_keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keyFile := filepath.Join(dir, "jwt_keys.json")
+
+	verify := func(token string) error {
+		_, err := jwt.ParseWithClaims(token, &Claims{}, jwtKeys.keyFunc)
+		return err
+	}
+
+	ks, err := NewJwtKeyStore(JwtAlgHS256, keyFile)
+	if err != nil {
+		t.Fatalf("NewJwtKeyStore failed: %v", err)
+	}
+	SetJwtKeyStore(ks)
+	hsToken := generateJWT("admin", []string{"admin"}, time.Now().Add(JwtValidInt))
+	if err = verify(hsToken); err != nil {
+		t.Fatalf("Token not verified: %v", err)
+	}
+
+	t.Run("RotateKeepsPreviousKey", func(t *testing.T) {
+		GenerateJwtSecretKey()
+		if err := verify(hsToken); err != nil {
+			t.Errorf("Token of previous key not verified: %v", err)
+		}
+		if len(ks.keys) != 2 {
+			t.Errorf("Expected 2 keys, got %d", len(ks.keys))
+		}
+	})
+
+	t.Run("ReloadFromFile", func(t *testing.T) {
+		reloaded, err := NewJwtKeyStore(JwtAlgHS256, keyFile)
+		if err != nil {
+			t.Fatalf("NewJwtKeyStore failed: %v", err)
+		}
+		if reloaded.current().Kid != ks.current().Kid {
+			t.Errorf("Reloaded signing key %v, expected %v", reloaded.current().Kid, ks.current().Kid)
+		}
+		SetJwtKeyStore(reloaded)
+		if err := verify(hsToken); err != nil {
+			t.Errorf("Token not verified after reload: %v", err)
+		}
+	})
+
+	for _, alg := range []string{JwtAlgES256, JwtAlgRS256} {
+		t.Run("SwitchTo"+alg, func(t *testing.T) {
+			switched, err := NewJwtKeyStore(alg, keyFile)
+			if err != nil {
+				t.Fatalf("NewJwtKeyStore failed: %v", err)
+			}
+			SetJwtKeyStore(switched)
+			if switched.current().Alg != alg {
+				t.Errorf("Signing key uses %v, expected %v", switched.current().Alg, alg)
+			}
+			token := generateJWT("admin", []string{"admin"}, time.Now().Add(JwtValidInt))
+			if err := verify(token); err != nil {
+				t.Errorf("%v token not verified: %v", alg, err)
+			}
+			if err := verify(hsToken); err != nil {
+				t.Errorf("HS256 token not verified after switching to %v: %v", alg, err)
+			}
+		})
+	}
+
+	t.Run("RejectAlgMismatch", func(t *testing.T) {
+		// HS256 token signed with the public key of the current key
+		claims := &Claims{Username: "admin", StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(JwtValidInt).Unix()}}
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		token.Header["kid"] = jwtKeys.current().Kid
+		forged, err := token.SignedString(jwtKeys.current().Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verify(forged); err == nil {
+			t.Errorf("Token with mismatching algorithm verified")
+		}
+	})
+
+	t.Run("RejectUnknownKid", func(t *testing.T) {
+		other, err := NewJwtKeyStore(JwtAlgHS256, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := other.sign(&Claims{Username: "admin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := verify(token); err == nil {
+			t.Errorf("Token of unknown key verified")
+		}
+	})
+
+	t.Run("InvalidAlg", func(t *testing.T) {
+		if _, err := NewJwtKeyStore("none", ""); err == nil {
+			t.Errorf("Expected error for unsupported algorithm")
+		}
+	})
+}
+
+func TestJwtSessionRevocation(t *testing.T) {
+	origKeys, origValidInt := jwtKeys, JwtValidInt
+	defer func() {
+		SetJwtKeyStore(origKeys)
+		JwtValidInt = origValidInt
+	}()
+	JwtValidInt = time.Hour
+	ks, err := NewJwtKeyStore(JwtAlgHS256, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetJwtKeyStore(ks)
+
+	s := &Server{config: &Config{UserAuth: AuthTypes{"jwt": true}}}
+	tokenCtx := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("access_token", token))
+	}
+	tokenJti := func(token string) string {
+		claims := &Claims{}
+		if _, err := jwt.ParseWithClaims(token, claims, jwtKeys.keyFunc); err != nil {
+			t.Fatalf("Invalid token: %v", err)
+		}
+		return claims.Id
+	}
+
+	adminToken := tokenResp("admin", []string{"admin"}).AccessToken
+	userToken := tokenResp("jwtuser", []string{"operator"}).AccessToken
+	adminCtx := tokenCtx(adminToken)
+	userJti := tokenJti(userToken)
+	if userJti == "" || userJti == tokenJti(adminToken) {
+		t.Fatalf("Expected distinct jti claims")
+	}
+
+	t.Run("ListSessions", func(t *testing.T) {
+		resp, err := s.ListSessions(adminCtx, &spb_jwt.ListSessionsRequest{Username: "jwtuser"})
+		if err != nil {
+			t.Fatalf("ListSessions failed: %v", err)
+		}
+		found := false
+		for _, session := range resp.GetSessions() {
+			if session.GetUsername() != "jwtuser" {
+				t.Errorf("Unexpected session of user %v", session.GetUsername())
+			}
+			if session.GetJti() == userJti {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Session %v not listed in %v", userJti, resp.GetSessions())
+		}
+	})
+
+	t.Run("OwnSessionsOnly", func(t *testing.T) {
+		userCtx := tokenCtx(userToken)
+		resp, err := s.ListSessions(userCtx, &spb_jwt.ListSessionsRequest{})
+		if err != nil {
+			t.Fatalf("ListSessions failed: %v", err)
+		}
+		for _, session := range resp.GetSessions() {
+			if session.GetUsername() != "jwtuser" {
+				t.Errorf("Session of user %v listed to jwtuser", session.GetUsername())
+			}
+		}
+		if _, err = s.ListSessions(userCtx, &spb_jwt.ListSessionsRequest{Username: "admin"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied listing sessions of another user, got %v", err)
+		}
+		if _, err = s.Revoke(userCtx, &spb_jwt.RevokeRequest{Username: "admin"}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied revoking sessions of another user, got %v", err)
+		}
+		if _, err = s.Revoke(userCtx, &spb_jwt.RevokeRequest{Jti: tokenJti(adminToken)}); status.Code(err) != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied revoking a session of another user, got %v", err)
+		}
+		if _, _, err = JwtAuthenAndAuthor(adminCtx); err != nil {
+			t.Errorf("Token of another user revoked: %v", err)
+		}
+	})
+
+	t.Run("RevokeByJti", func(t *testing.T) {
+		resp, err := s.Revoke(adminCtx, &spb_jwt.RevokeRequest{Jti: userJti})
+		if err != nil {
+			t.Fatalf("Revoke failed: %v", err)
+		}
+		if len(resp.GetSessions()) != 1 || !resp.GetSessions()[0].GetRevoked() {
+			t.Errorf("Unexpected revoked sessions %v", resp.GetSessions())
+		}
+		_, _, err = JwtAuthenAndAuthor(tokenCtx(userToken))
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Revoked token should be rejected, got %v", err)
+		}
+		if _, _, err = JwtAuthenAndAuthor(adminCtx); err != nil {
+			t.Errorf("Token not revoked should be accepted: %v", err)
+		}
+	})
+
+	t.Run("RevocationPersisted", func(t *testing.T) {
+		reloaded := &jwtSessionStore{}
+		if revoked, err := reloaded.isRevoked(userJti); err != nil || !revoked {
+			t.Errorf("Revocation of %v not loaded from STATE_DB: %v", userJti, err)
+		}
+	})
+
+	t.Run("LoadRetried", func(t *testing.T) {
+		mockAddr := gomonkey.ApplyFunc(sdcfg.GetDbTcpAddr, func(dbName string, ns string) (string, error) {
+			return "", fmt.Errorf("no STATE_DB")
+		})
+		reloaded := &jwtSessionStore{}
+		if _, err := reloaded.isRevoked(userJti); status.Code(err) != codes.Unavailable {
+			t.Errorf("Expected Unavailable before the sessions are loaded, got %v", err)
+		}
+		mockAddr.Reset()
+		if revoked, err := reloaded.isRevoked(userJti); err != nil || !revoked {
+			t.Errorf("Revocation of %v not loaded by the retry: %v", userJti, err)
+		}
+	})
+
+	t.Run("RevokeErrors", func(t *testing.T) {
+		_, err := s.Revoke(adminCtx, &spb_jwt.RevokeRequest{})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument, got %v", err)
+		}
+		_, err = s.Revoke(adminCtx, &spb_jwt.RevokeRequest{Jti: "unknown"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound, got %v", err)
+		}
+		if _, err = s.Revoke(adminCtx, &spb_jwt.RevokeRequest{Jti: userJti}); err != nil {
+			t.Errorf("Revoking a revoked session failed: %v", err)
+		}
+	})
+
+	t.Run("RevokeByUsername", func(t *testing.T) {
+		if _, err := s.Revoke(adminCtx, &spb_jwt.RevokeRequest{Username: "admin"}); err != nil {
+			t.Fatalf("Revoke failed: %v", err)
+		}
+		if _, err := s.ListSessions(adminCtx, &spb_jwt.ListSessionsRequest{}); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Revoked token should be rejected, got %v", err)
+		}
+	})
+}
+
 func TestMain(m *testing.M) {
 	defer test_utils.MemLeakCheck()
 	m.Run()
@@ -6,6 +6,7 @@ import (
 	spb "github.com/sonic-net/sonic-gnmi/proto/gnoi"
 	spb_jwt "github.com/sonic-net/sonic-gnmi/proto/gnoi/jwt"
 	"context"
+	"io"
 	"os"
 	"os/signal"
 	"fmt"
@@ -22,6 +23,8 @@ var (
 	args = flag.String("jsonin", "", "RPC Arguments in json format")
 	jwtToken = flag.String("jwt_token", "", "JWT Token if required")
 	targetName = flag.String("target_name", "hostname.com", "The target name use to verify the hostname returned by TLS handshake")
+	netns = flag.String("netns", "", "Network namespace of Ping and Traceroute")
+	vrf = flag.String("vrf", "", "VRF of Ping and Traceroute")
 )
 func setUserCreds(ctx context.Context) context.Context {
 	if len(*jwtToken) > 0 {
@@ -59,6 +62,10 @@ func main() {
 			systemRebootStatus(sc, ctx)
 		case "KillProcess":
 			killProcess(sc, ctx)
+		case "Ping":
+			systemPing(sc, ctx)
+		case "Traceroute":
+			systemTraceroute(sc, ctx)
 		default:
 			panic("Invalid RPC Name")
 		}
@@ -85,6 +92,12 @@ func main() {
 		case "refresh":
 			sc := spb_jwt.NewSonicJwtServiceClient(conn)
 			refresh(sc, ctx)
+		case "revoke":
+			sc := spb_jwt.NewSonicJwtServiceClient(conn)
+			revoke(sc, ctx)
+		case "listSessions":
+			sc := spb_jwt.NewSonicJwtServiceClient(conn)
+			listSessions(sc, ctx)
 		case "clearNeighbors":
 			sc := spb.NewSonicServiceClient(conn)
 			clearNeighbors(sc, ctx)
@@ -167,6 +180,72 @@ func systemRebootStatus(sc gnoi_system_pb.SystemClient, ctx context.Context) {
 	fmt.Println(string(respstr))
 }
 
+func setNetworkInstance(ctx context.Context) context.Context {
+	if len(*netns) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, "network-namespace", *netns)
+	}
+	if len(*vrf) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, "vrf", *vrf)
+	}
+	return ctx
+}
+
+func systemPing(sc gnoi_system_pb.SystemClient, ctx context.Context) {
+	fmt.Println("System Ping")
+	ctx = setNetworkInstance(setUserCreds(ctx))
+	req := &gnoi_system_pb.PingRequest {}
+	err := json.Unmarshal([]byte(*args), req)
+	if err != nil {
+		panic(err.Error())
+	}
+	stream, err := sc.Ping(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err.Error())
+		}
+		respstr, err := json.Marshal(resp)
+		if err != nil {
+			panic(err.Error())
+		}
+		fmt.Println(string(respstr))
+	}
+}
+
+func systemTraceroute(sc gnoi_system_pb.SystemClient, ctx context.Context) {
+	fmt.Println("System Traceroute")
+	ctx = setNetworkInstance(setUserCreds(ctx))
+	req := &gnoi_system_pb.TracerouteRequest {}
+	err := json.Unmarshal([]byte(*args), req)
+	if err != nil {
+		panic(err.Error())
+	}
+	stream, err := sc.Traceroute(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(err.Error())
+		}
+		respstr, err := json.Marshal(resp)
+		if err != nil {
+			panic(err.Error())
+		}
+		fmt.Println(string(respstr))
+	}
+}
+
 func sonicShowTechSupport(sc spb.SonicServiceClient, ctx context.Context) {
 	fmt.Println("Sonic ShowTechsupport")
 	ctx = setUserCreds(ctx)
@@ -303,6 +382,42 @@ func refresh(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
 	fmt.Println(string(respstr))
 }
 
+func revoke(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
+	fmt.Println("Sonic Revoke")
+	ctx = setUserCreds(ctx)
+	req := &spb_jwt.RevokeRequest {}
+
+	json.Unmarshal([]byte(*args), req)
+
+	resp,err := sc.Revoke(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
+func listSessions(sc spb_jwt.SonicJwtServiceClient, ctx context.Context) {
+	fmt.Println("Sonic ListSessions")
+	ctx = setUserCreds(ctx)
+	req := &spb_jwt.ListSessionsRequest {}
+
+	json.Unmarshal([]byte(*args), req)
+
+	resp,err := sc.ListSessions(ctx, req)
+	if err != nil {
+		panic(err.Error())
+	}
+	respstr, err := json.Marshal(resp)
+	if err != nil {
+		panic(err.Error())
+	}
+	fmt.Println(string(respstr))
+}
+
 func clearNeighbors(sc spb.SonicServiceClient, ctx context.Context) {
     fmt.Println("Sonic ClearNeighbors")
     ctx = setUserCreds(ctx)
@@ -0,0 +1,311 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/golang/glog"
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Prefix of gNMI alias names, defined by the gNMI specification.
+const aliasNamePrefix = "#"
+
+// Prefix of the alias names created by the server.
+const serverAliasNamePrefix = "#s"
+
+// Maximum number of aliases of a subscribe session.
+const maxAliases = 1024
+
+type aliasEntry struct {
+	name string
+	path *gnmipb.Path
+}
+
+// Aliases holds the gNMI path aliases of a subscribe session. Client-defined
+// aliases are added from the SubscribeRequest aliases message; if the client
+// set use_aliases, the server also defines its own aliases for the paths it
+// streams. Aliases are applied to the notification prefix by ValToResp.
+type Aliases struct {
+	mu            sync.Mutex
+	serverDefined bool
+	nextId        int
+	entries       map[string]*aliasEntry // keyed by aliasPathKey
+	names         map[string]string      // alias name to aliasPathKey
+}
+
+// NewAliases returns an empty alias table.
+func NewAliases() *Aliases {
+	return &Aliases{
+		entries: make(map[string]*aliasEntry),
+		names:   make(map[string]string),
+	}
+}
+
+// UseServerAliases lets the server define aliases for the paths it streams,
+// as requested by the use_aliases field of the SubscriptionList.
+func (a *Aliases) UseServerAliases() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.serverDefined = true
+}
+
+func (a *Aliases) useServerAliases() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.serverDefined
+}
+
+// Add registers a client-defined alias. An alias with an empty path removes
+// the alias of the same name.
+func (a *Aliases) Add(alias *gnmipb.Alias) error {
+	name := alias.GetAlias()
+	if !strings.HasPrefix(name, aliasNamePrefix) || len(name) == len(aliasNamePrefix) {
+		return fmt.Errorf("invalid alias name %q, must start with %q", name, aliasNamePrefix)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if key, ok := a.names[name]; ok {
+		delete(a.entries, key)
+		delete(a.names, name)
+	}
+	path := alias.GetPath()
+	if len(path.GetElem()) == 0 {
+		log.V(2).Infof("Alias %v removed", name)
+		return nil
+	}
+	key := aliasPathKey(path)
+	if old, ok := a.entries[key]; ok {
+		delete(a.names, old.name)
+	} else if len(a.entries) >= maxAliases {
+		return fmt.Errorf("too many aliases, at most %d are allowed", maxAliases)
+	}
+	a.entries[key] = &aliasEntry{name: name, path: path}
+	a.names[name] = key
+	log.V(2).Infof("Alias %v added for %v", name, path)
+	return nil
+}
+
+// AddList registers all aliases of a SubscribeRequest aliases message.
+func (a *Aliases) AddList(list *gnmipb.AliasList) error {
+	for _, alias := range list.GetAlias() {
+		if err := a.Add(alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// define creates a server-defined alias for path and returns it. It returns
+// nil when the server does not define aliases, path is already aliased or
+// the alias table is full.
+func (a *Aliases) define(path *gnmipb.Path) *aliasEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.serverDefined || len(path.GetElem()) == 0 {
+		return nil
+	}
+	key := aliasPathKey(path)
+	if _, ok := a.entries[key]; ok {
+		return nil
+	}
+	if len(a.entries) >= maxAliases {
+		log.V(2).Infof("Server alias not defined for %v, alias table is full", path)
+		return nil
+	}
+	name := ""
+	for name == "" || a.names[name] != "" {
+		a.nextId++
+		name = fmt.Sprintf("%s%d", serverAliasNamePrefix, a.nextId)
+	}
+	entry := &aliasEntry{name: name, path: path}
+	a.entries[key] = entry
+	a.names[name] = key
+	log.V(2).Infof("Server alias %v defined for %v", name, path)
+	return entry
+}
+
+// lookup returns the longest alias whose path covers prefix and leading
+// elements common to all paths of the notification, and the number of
+// elements it consumes from those paths.
+func (a *Aliases) lookup(prefix *gnmipb.Path, paths []*gnmipb.Path) (*aliasEntry, int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var found *aliasEntry
+	consumed := -1
+	prefixElems := prefix.GetElem()
+	for _, entry := range a.entries {
+		if entry.path.GetTarget() != prefix.GetTarget() || entry.path.GetOrigin() != prefix.GetOrigin() {
+			continue
+		}
+		elems := entry.path.GetElem()
+		if len(elems) < len(prefixElems) || !elemsHavePrefix(elems, prefixElems) {
+			continue
+		}
+		rest := elems[len(prefixElems):]
+		matched := true
+		for _, p := range paths {
+			if !elemsHavePrefix(p.GetElem(), rest) {
+				matched = false
+				break
+			}
+		}
+		if matched && len(rest) > consumed {
+			found = entry
+			consumed = len(rest)
+		}
+	}
+	return found, consumed
+}
+
+// notificationPaths returns the update and delete paths of a notification.
+func notificationPaths(n *gnmipb.Notification) []*gnmipb.Path {
+	var paths []*gnmipb.Path
+	for _, u := range n.GetUpdate() {
+		paths = append(paths, u.GetPath())
+	}
+	paths = append(paths, n.GetDelete()...)
+	return paths
+}
+
+// serverAliasPath returns the path the server aliases for a notification:
+// its prefix followed by the leading elements common to all its paths. The
+// last element of each path is never part of it, so that the alias is shared
+// by the sibling leaves of later notifications.
+func serverAliasPath(n *gnmipb.Notification) *gnmipb.Path {
+	prefix := n.GetPrefix()
+	paths := notificationPaths(n)
+	var common []*gnmipb.PathElem
+	for i, p := range paths {
+		elems := p.GetElem()
+		if len(elems) > 0 {
+			elems = elems[:len(elems)-1]
+		}
+		if i == 0 {
+			common = elems
+			continue
+		}
+		common = commonElems(common, elems)
+	}
+	elems := append(append([]*gnmipb.PathElem{}, prefix.GetElem()...), common...)
+	return &gnmipb.Path{
+		Origin: prefix.GetOrigin(),
+		Target: prefix.GetTarget(),
+		Elem:   elems,
+	}
+}
+
+// announce returns the notification defining a new server alias for n,
+// or nil if none is needed.
+func (a *Aliases) announce(n *gnmipb.Notification) *gnmipb.Notification {
+	if a == nil || !a.useServerAliases() {
+		return nil
+	}
+	if entry, _ := a.lookup(n.GetPrefix(), notificationPaths(n)); entry != nil {
+		return nil
+	}
+	entry := a.define(serverAliasPath(n))
+	if entry == nil {
+		return nil
+	}
+	return &gnmipb.Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    entry.path,
+		Alias:     entry.name,
+	}
+}
+
+// apply returns a copy of the notification n with its prefix replaced by the
+// best matching alias, or n itself if no alias matches.
+func (a *Aliases) apply(n *gnmipb.Notification) *gnmipb.Notification {
+	if a == nil || n == nil {
+		return n
+	}
+	entry, consumed := a.lookup(n.GetPrefix(), notificationPaths(n))
+	if entry == nil {
+		return n
+	}
+
+	trim := func(p *gnmipb.Path) *gnmipb.Path {
+		return &gnmipb.Path{Origin: p.GetOrigin(), Elem: p.GetElem()[consumed:]}
+	}
+	aliased := &gnmipb.Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix: &gnmipb.Path{
+			Elem: []*gnmipb.PathElem{{Name: entry.name}},
+		},
+		Atomic: n.GetAtomic(),
+	}
+	for _, u := range n.GetUpdate() {
+		aliased.Update = append(aliased.Update, &gnmipb.Update{
+			Path:       trim(u.GetPath()),
+			Val:        u.GetVal(),
+			Duplicates: u.GetDuplicates(),
+		})
+	}
+	for _, d := range n.GetDelete() {
+		aliased.Delete = append(aliased.Delete, trim(d))
+	}
+	return aliased
+}
+
+// aliasPathKey returns a canonical string of the target, origin and elements of path.
+func aliasPathKey(path *gnmipb.Path) string {
+	var b strings.Builder
+	b.WriteString(path.GetTarget())
+	b.WriteString("|")
+	b.WriteString(path.GetOrigin())
+	for _, e := range path.GetElem() {
+		b.WriteString("/")
+		b.WriteString(e.GetName())
+		keys := make([]string, 0, len(e.GetKey()))
+		for k := range e.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString("[" + k + "=" + e.GetKey()[k] + "]")
+		}
+	}
+	return b.String()
+}
+
+func elemEqual(a, b *gnmipb.PathElem) bool {
+	if a.GetName() != b.GetName() || len(a.GetKey()) != len(b.GetKey()) {
+		return false
+	}
+	for k, v := range a.GetKey() {
+		if bv, ok := b.GetKey()[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// elemsHavePrefix reports whether elems starts with prefix.
+func elemsHavePrefix(elems, prefix []*gnmipb.PathElem) bool {
+	if len(elems) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if !elemEqual(elems[i], prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonElems returns the longest leading elements shared by a and b.
+func commonElems(a, b []*gnmipb.PathElem) []*gnmipb.PathElem {
+	i := 0
+	for i < len(a) && i < len(b) && elemEqual(a[i], b[i]) {
+		i++
+	}
+	return a[:i]
+}
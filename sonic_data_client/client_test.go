@@ -1,6 +1,7 @@
 package client
 
 import (
+    "context"
     "sync"
     "errors"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/sonic-net/sonic-gnmi/swsscommon"
 	"github.com/sonic-net/sonic-gnmi/test_utils"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	spb "github.com/sonic-net/sonic-gnmi/proto"
 )
 
 var testFile string = "/etc/sonic/ut.cp.json"
@@ -829,6 +831,201 @@ func TestGetZmqClient(t *testing.T) {
 	}
 }
 
+func TestPathElemNames(t *testing.T) {
+	model := `
+module sonic-interface {
+	namespace "http://github.com/sonic-net/sonic-interface";
+	prefix intf;
+	container sonic-interface {
+		container INTERFACE {
+			list INTERFACE_LIST {
+				key "name";
+				leaf name { type string; }
+			}
+			list INTERFACE_IPPREFIX_LIST {
+				key "name ip-prefix";
+				leaf name { type string; }
+				leaf ip-prefix { type string; }
+			}
+		}
+	}
+}
+`
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(dir+"/sonic-interface.yang", []byte(model), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sonicYangModelsDir = dir
+	tableKeysOnce = sync.Once{}
+	defer func() {
+		sonicYangModelsDir = "/usr/local/yang-models"
+		tableKeysOnce = sync.Once{}
+	}()
+
+	tests := []struct {
+		desc    string
+		elem    *gnmipb.PathElem
+		want    []string
+		wantErr bool
+	}{
+		{
+			desc: "no key",
+			elem: &gnmipb.PathElem{Name: "PORT"},
+			want: []string{"PORT"},
+		},
+		{
+			desc: "single key",
+			elem: &gnmipb.PathElem{Name: "PORT", Key: map[string]string{"name": "Ethernet0"}},
+			want: []string{"PORT", "Ethernet0"},
+		},
+		{
+			desc: "joined multi-part key",
+			elem: &gnmipb.PathElem{Name: "INTERFACE", Key: map[string]string{"name": "Ethernet0|10.0.0.1/24"}},
+			want: []string{"INTERFACE", "Ethernet0|10.0.0.1/24"},
+		},
+		{
+			// Alphabetical order of the key names is not the table key order
+			desc: "multiple keys",
+			elem: &gnmipb.PathElem{Name: "INTERFACE", Key: map[string]string{"name": "Ethernet0", "ip-prefix": "10.0.0.1/24"}},
+			want: []string{"INTERFACE", "Ethernet0|10.0.0.1/24"},
+		},
+		{
+			desc:    "multiple keys not of the table",
+			elem:    &gnmipb.PathElem{Name: "INTERFACE", Key: map[string]string{"name": "Ethernet0", "vrf": "Vrf1"}},
+			wantErr: true,
+		},
+		{
+			desc:    "multiple keys of an unknown table",
+			elem:    &gnmipb.PathElem{Name: "COUNTERS", Key: map[string]string{"name": "Ethernet0", "index": "0"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := pathElemNames(tt.elem, "|")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pathElemNames(%v) error = %v, want error %v", tt.elem, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pathElemNames(%v) = %v, want %v", tt.elem, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupGetFuncKeyed(t *testing.T) {
+	prefix := &gnmipb.Path{Target: "OTHERS"}
+	path := &gnmipb.Path{Elem: []*gnmipb.PathElem{
+		{Name: "platform"},
+		{Name: "cpu", Key: map[string]string{"name": "0"}},
+	}}
+	if _, err := lookupGetFunc(prefix, path); err == nil {
+		t.Errorf("lookupGetFunc(%v) succeeded, want error for keyed path", path)
+	}
+}
+
+func TestNewSubscribeClientUnsupported(t *testing.T) {
+	paths := []*gnmipb.Path{{Elem: []*gnmipb.PathElem{{Name: "COUNTERS"}}}}
+	tests := []struct {
+		desc   string
+		origin string
+		prefix *gnmipb.Path
+	}{
+		{desc: "unknown origin", origin: "unknown", prefix: &gnmipb.Path{Target: "COUNTERS_DB"}},
+		{desc: "empty target", prefix: &gnmipb.Path{}},
+		{desc: "nil prefix", prefix: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			dc, err := NewSubscribeClient(context.Background(), tt.prefix, paths, tt.origin, nil, 3)
+			if _, ok := err.(*UnsupportedSubscribeError); !ok || dc != nil {
+				t.Errorf("NewSubscribeClient = %v, %v, want UnsupportedSubscribeError", dc, err)
+			}
+		})
+	}
+}
+
+func TestEventsPollRunQueueError(t *testing.T) {
+	// The background routines run until the client is stopped
+	background := func(evtc *EventClient) {
+		defer evtc.wg.Done()
+		for !evtc.isStopped() {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	mockGet := gomonkey.ApplyFunc(get_events, background)
+	defer mockGet.Reset()
+	mockStats := gomonkey.ApplyFunc(update_stats, background)
+	defer mockStats.Reset()
+
+	q := queue.NewPriorityQueue(1, false)
+	q.Dispose()
+	evtc := &EventClient{pollCache: []*spb.Value{{Timestamp: 1}}}
+	poll := make(chan struct{}, 1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go evtc.PollRun(q, poll, &wg, nil)
+	poll <- struct{}{}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("PollRun did not stop its routines on queue error")
+	}
+}
+
+func TestServerAliases(t *testing.T) {
+	prefix := &gnmipb.Path{Target: "COUNTERS_DB"}
+	leaf := func(names ...string) *gnmipb.Notification {
+		var elems []*gnmipb.PathElem
+		for _, name := range names {
+			elems = append(elems, &gnmipb.PathElem{Name: name})
+		}
+		return &gnmipb.Notification{
+			Prefix: prefix,
+			Update: []*gnmipb.Update{{Path: &gnmipb.Path{Elem: elems}}},
+		}
+	}
+
+	aliases := NewAliases()
+	aliases.UseServerAliases()
+
+	// Only the shared prefix of a single update is aliased
+	n := aliases.announce(leaf("COUNTERS", "Ethernet0", "SAI_PORT_STAT_IF_IN_OCTETS"))
+	if n == nil || n.GetAlias() != "#s1" || len(n.GetPrefix().GetElem()) != 2 {
+		t.Fatalf("got alias notification %v, want #s1 for COUNTERS/Ethernet0", n)
+	}
+	// Sibling leaves reuse it
+	if n = aliases.announce(leaf("COUNTERS", "Ethernet0", "SAI_PORT_STAT_IF_OUT_OCTETS")); n != nil {
+		t.Errorf("got alias notification %v for a sibling leaf", n)
+	}
+	aliased := aliases.apply(leaf("COUNTERS", "Ethernet0", "SAI_PORT_STAT_IF_OUT_OCTETS"))
+	if aliased.GetPrefix().GetElem()[0].GetName() != "#s1" || len(aliased.GetUpdate()[0].GetPath().GetElem()) != 1 {
+		t.Errorf("got aliased notification %v, want #s1 and one element", aliased)
+	}
+	// Top level leaves are not aliased
+	if n = aliases.announce(leaf("COUNTERS_PORT_NAME_MAP")); n != nil {
+		t.Errorf("got alias notification %v for a top level leaf", n)
+	}
+
+	// The alias table is capped
+	for i := 0; i < maxAliases; i++ {
+		aliases.announce(leaf("COUNTERS", fmt.Sprintf("Ethernet%d", i+1), "SAI_PORT_STAT_IF_IN_OCTETS"))
+	}
+	if len(aliases.entries) != maxAliases {
+		t.Errorf("got %d aliases, want %d", len(aliases.entries), maxAliases)
+	}
+	err := aliases.Add(&gnmipb.Alias{Alias: "#full", Path: &gnmipb.Path{Elem: []*gnmipb.PathElem{{Name: "COUNTERS"}}}})
+	if err == nil {
+		t.Errorf("Add should fail when the alias table is full")
+	}
+}
+
 func TestMain(m *testing.M) {
 	defer test_utils.MemLeakCheck()
 	m.Run()
@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -18,6 +20,7 @@ import (
 	"github.com/Workiva/go-datastructures/queue"
 	"github.com/go-redis/redis"
 	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/goyang/pkg/yang"
 )
 
 const (
@@ -161,6 +164,8 @@ type DbClient struct {
 	pathG2S map[*gnmipb.Path][]tablePath
 	q       *queue.PriorityQueue
 	channel chan struct{}
+	// dataType limits Get to the data of one gNMI GetRequest type
+	dataType gnmipb.GetRequest_DataType
 
 	synced sync.WaitGroup  // Control when to send gNMI sync_response
 	w      *sync.WaitGroup // wait for all sub go routines to finish
@@ -171,7 +176,48 @@ type DbClient struct {
 	errors  int64
 }
 
-func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
+// ClientOption is an optional setting applied to a data client on creation.
+type ClientOption interface {
+	IsClientOption()
+}
+
+// DataTypeOption restricts Get to the data of the given gNMI GetRequest type.
+type DataTypeOption struct {
+	DataType gnmipb.GetRequest_DataType
+}
+
+func (o DataTypeOption) IsClientOption() {}
+
+func (o DataTypeOption) IsTranslClientOption() {}
+
+// getDataType returns the GetRequest data type carried by opts, ALL by default.
+func getDataType(opts []ClientOption) gnmipb.GetRequest_DataType {
+	dataType := gnmipb.GetRequest_ALL
+	for _, o := range opts {
+		if dt, ok := o.(DataTypeOption); ok {
+			dataType = dt.DataType
+		}
+	}
+	return dataType
+}
+
+// IsDbOfDataType reports whether the DB dbName holds data of the given
+// gNMI GetRequest data type. CONFIG_DB is the only source of configuration,
+// every other DB holds state. COUNTERS_DB, STATE_DB and the non-DB data
+// (OTHERS) are not derived from configuration and are treated as operational.
+func IsDbOfDataType(dbName string, dataType gnmipb.GetRequest_DataType) bool {
+	switch dataType {
+	case gnmipb.GetRequest_CONFIG:
+		return dbName == "CONFIG_DB"
+	case gnmipb.GetRequest_STATE:
+		return dbName != "CONFIG_DB"
+	case gnmipb.GetRequest_OPERATIONAL:
+		return dbName == "COUNTERS_DB" || dbName == "STATE_DB" || dbName == "OTHERS"
+	}
+	return true
+}
+
+func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, opts ...ClientOption) (Client, error) {
 	var client DbClient
 	var err error
 
@@ -181,6 +227,7 @@ func NewDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
 	}
 
 	client.prefix = prefix
+	client.dataType = getDataType(opts)
 	client.pathG2S = make(map[*gnmipb.Path][]tablePath)
 	err = populateAllDbtablePath(prefix, paths, &client.pathG2S)
 
@@ -212,7 +259,7 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 		for gnmiPath := range c.pathG2S {
 			c.w.Add(1)
 			c.synced.Add(1)
-			go streamOnChangeSubscription(c, gnmiPath)
+			go streamOnChangeSubscription(c, &gnmipb.Subscription{Path: gnmiPath})
 		}
 	} else {
 		log.V(2).Infof("Stream subscription request received, mode: %v, subscription count: %v",
@@ -222,6 +269,10 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 		for _, sub := range subscribe.GetSubscription() {
 			log.V(2).Infof("Sub mode: %v, path: %v", sub.GetMode(), sub.GetPath())
 			subMode := sub.GetMode()
+			if subMode == gnmipb.SubscriptionMode_TARGET_DEFINED {
+				subMode = targetDefinedMode(c.pathG2S[sub.GetPath()])
+				log.V(2).Infof("Target defined mode of path %v: %v", sub.GetPath(), subMode)
+			}
 
 			if subMode == gnmipb.SubscriptionMode_SAMPLE {
 				c.w.Add(1)      // wait group to indicate the streaming session is complete.
@@ -230,7 +281,7 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 			} else if subMode == gnmipb.SubscriptionMode_ON_CHANGE {
 				c.w.Add(1)
 				c.synced.Add(1)
-				go streamOnChangeSubscription(c, sub.GetPath())
+				go streamOnChangeSubscription(c, sub)
 			} else {
 				enqueueFatalMsg(c, fmt.Sprintf("unsupported subscription mode, %v", subMode))
 				return
@@ -254,44 +305,72 @@ func (c *DbClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, w *sync
 	log.V(1).Infof("Exiting StreamRun routine for Client %v", c)
 }
 
+// targetDefinedMode returns the mode of TARGET_DEFINED subscriptions to
+// tblPaths. Counters change all the time and are sampled, while the other
+// tables, including the name maps of COUNTERS_DB, are streamed on change.
+func targetDefinedMode(tblPaths []tablePath) gnmipb.SubscriptionMode {
+	for _, tblPath := range tblPaths {
+		if tblPath.dbName == "COUNTERS_DB" && !strings.HasSuffix(tblPath.tableName, "_MAP") {
+			return gnmipb.SubscriptionMode_SAMPLE
+		}
+	}
+	return gnmipb.SubscriptionMode_ON_CHANGE
+}
+
 // streamOnChangeSubscription implements Subscription "ON_CHANGE STREAM" mode
-func streamOnChangeSubscription(c *DbClient, gnmiPath *gnmipb.Path) {
+func streamOnChangeSubscription(c *DbClient, sub *gnmipb.Subscription) {
+	heartbeatInterval, err := validateHeartbeatInterval(sub)
+	if err != nil {
+		enqueueFatalMsg(c, err.Error())
+		c.synced.Done()
+		c.w.Done()
+		return
+	}
+
+	gnmiPath := sub.GetPath()
 	tblPaths := c.pathG2S[gnmiPath]
 	log.V(2).Infof("streamOnChangeSubscription gnmiPath: %v", gnmiPath)
 
 	if tblPaths[0].field != "" {
+		// sample interval is not applicable, changes come from keyspace notifications
 		if len(tblPaths) > 1 {
-			go dbFieldMultiSubscribe(c, gnmiPath, true, time.Millisecond*200, false)
+			go dbFieldMultiSubscribe(c, gnmiPath, true, 0, false, heartbeatInterval, false)
 		} else {
-			go dbFieldSubscribe(c, gnmiPath, true, time.Millisecond*200)
+			go dbFieldSubscribe(c, gnmiPath, true, 0, heartbeatInterval, false)
 		}
 	} else {
 		// sample interval and update only parameters are not applicable
-		go dbTableKeySubscribe(c, gnmiPath, 0, true)
+		go dbTableKeySubscribe(c, gnmiPath, 0, true, heartbeatInterval, false)
 	}
 }
 
 // streamSampleSubscription implements Subscription "SAMPLE STREAM" mode
 func streamSampleSubscription(c *DbClient, sub *gnmipb.Subscription, updateOnly bool) {
+	var heartbeatInterval time.Duration
 	samplingInterval, err := validateSampleInterval(sub)
+	if err == nil && sub.GetSuppressRedundant() {
+		// Heartbeats only apply to samples when redundant ones are suppressed
+		heartbeatInterval, err = validateHeartbeatInterval(sub)
+	}
 	if err != nil {
 		enqueueFatalMsg(c, err.Error())
 		c.synced.Done()
 		c.w.Done()
 		return
 	}
+	suppressRedundant := sub.GetSuppressRedundant()
 
 	gnmiPath := sub.GetPath()
 	tblPaths := c.pathG2S[gnmiPath]
 	log.V(2).Infof("streamSampleSubscription gnmiPath: %v", gnmiPath)
 	if tblPaths[0].field != "" {
 		if len(tblPaths) > 1 {
-			dbFieldMultiSubscribe(c, gnmiPath, false, samplingInterval, updateOnly)
+			dbFieldMultiSubscribe(c, gnmiPath, false, samplingInterval, updateOnly, heartbeatInterval, suppressRedundant)
 		} else {
-			dbFieldSubscribe(c, gnmiPath, false, samplingInterval)
+			dbFieldSubscribe(c, gnmiPath, false, samplingInterval, heartbeatInterval, suppressRedundant)
 		}
 	} else {
-		dbTableKeySubscribe(c, gnmiPath, samplingInterval, updateOnly)
+		dbTableKeySubscribe(c, gnmiPath, samplingInterval, updateOnly, heartbeatInterval, suppressRedundant)
 	}
 }
 
@@ -336,7 +415,43 @@ func (c *DbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sync.W
 	}
 }
 func (c *DbClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-	return
+	c.w = w
+	defer c.w.Done()
+	c.q = q
+	c.channel = once
+
+	_, more := <-c.channel
+	if !more {
+		log.V(1).Infof("%v once channel closed, exiting onceDb routine", c)
+		return
+	}
+	t1 := time.Now()
+	for gnmiPath, tblPaths := range c.pathG2S {
+		val, err := tableData2TypedValue(tblPaths, nil)
+		if err != nil {
+			log.V(2).Infof("Unable to create gnmi TypedValue due to err: %v", err)
+			enqueueFatalMsg(c, err.Error())
+			return
+		}
+
+		spbv := &spb.Value{
+			Prefix:       c.prefix,
+			Path:         gnmiPath,
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: false,
+			Val:          val,
+		}
+		c.q.Put(Value{spbv})
+		log.V(6).Infof("Added spbv #%v", spbv)
+	}
+
+	c.q.Put(Value{
+		&spb.Value{
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: true,
+		},
+	})
+	log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 func (c *DbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	// wait sync for Get, not used for now
@@ -344,6 +459,10 @@ func (c *DbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 
 	var values []*spb.Value
 	ts := time.Now()
+	if dbName, _, _, _ := IsTargetDb(c.prefix.GetTarget()); !IsDbOfDataType(dbName, c.dataType) {
+		log.V(4).Infof("%v holds no %v data", dbName, c.dataType)
+		return values, nil
+	}
 	for gnmiPath, tblPaths := range c.pathG2S {
 		val, err := tableData2TypedValue(tblPaths, nil)
 		if err != nil {
@@ -368,8 +487,9 @@ func (c *DbClient) Close() error {
 }
 
 // Convert from SONiC Value to its corresponding gNMI proto stream
-// response type.
-func ValToResp(val Value) (*gnmipb.SubscribeResponse, error) {
+// response type. The notification prefix is replaced by its alias when
+// aliases is not nil and holds a matching alias.
+func ValToResp(val Value, aliases *Aliases) (*gnmipb.SubscribeResponse, error) {
 	switch val.GetSyncResponse() {
 	case true:
 		return &gnmipb.SubscribeResponse{
@@ -383,48 +503,52 @@ func ValToResp(val Value) (*gnmipb.SubscribeResponse, error) {
 			return nil, fmt.Errorf("%s", fatal)
 		}
 
-		// In case the client returned a full gnmipb.Notification object
-		if n := val.GetNotification(); n != nil {
-			return &gnmipb.SubscribeResponse{
-				Response: &gnmipb.SubscribeResponse_Update{Update: n}}, nil
-		}
-
-		// In case of path deletion
-		if deleted := val.GetDelete(); deleted != nil {
-			return &gnmipb.SubscribeResponse{
-				Response: &gnmipb.SubscribeResponse_Update{
-					Update: &gnmipb.Notification{
-						Timestamp: val.GetTimestamp(),
-						Prefix:    val.GetPrefix(),
-						Delete:    deleted,
-						Update: []*gnmipb.Update{
-							{
-								Path: val.GetPath(),
-								Val:  val.GetVal(),
-							},
-						},
-					},
-				},
-			}, nil
-		}
-
 		return &gnmipb.SubscribeResponse{
 			Response: &gnmipb.SubscribeResponse_Update{
-				Update: &gnmipb.Notification{
-					Timestamp: val.GetTimestamp(),
-					Prefix:    val.GetPrefix(),
-					Update: []*gnmipb.Update{
-						{
-							Path: val.GetPath(),
-							Val:  val.GetVal(),
-						},
-					},
-				},
+				Update: aliases.apply(valToNotification(val)),
 			},
 		}, nil
 	}
 }
 
+// ValToAliasResp returns the response defining a new server alias for the
+// notification of val, or nil if no new alias is needed.
+func ValToAliasResp(val Value, aliases *Aliases) *gnmipb.SubscribeResponse {
+	if aliases == nil || val.GetSyncResponse() || val.GetFatal() != "" {
+		return nil
+	}
+	n := aliases.announce(valToNotification(val))
+	if n == nil {
+		return nil
+	}
+	return &gnmipb.SubscribeResponse{
+		Response: &gnmipb.SubscribeResponse_Update{Update: n},
+	}
+}
+
+func valToNotification(val Value) *gnmipb.Notification {
+	// In case the client returned a full gnmipb.Notification object
+	if n := val.GetNotification(); n != nil {
+		return n
+	}
+
+	n := &gnmipb.Notification{
+		Timestamp: val.GetTimestamp(),
+		Prefix:    val.GetPrefix(),
+		Update: []*gnmipb.Update{
+			{
+				Path: val.GetPath(),
+				Val:  val.GetVal(),
+			},
+		},
+	}
+	// In case of path deletion
+	if deleted := val.GetDelete(); deleted != nil {
+		n.Delete = deleted
+	}
+	return n
+}
+
 func GetTableKeySeparator(target string, ns string) (string, error) {
 	_, ok := spb.Target_value[target]
 	if !ok {
@@ -560,6 +684,120 @@ func gnmiFullPath(prefix, path *gnmipb.Path) *gnmipb.Path {
 	return fullPath
 }
 
+// sonicYangModelsDir holds the sonic-yang models, which give the key order of
+// the tables.
+var sonicYangModelsDir = "/usr/local/yang-models"
+
+var (
+	tableKeysOnce sync.Once
+	tableKeys     map[string][][]string
+	tableKeysErr  error
+)
+
+// loadTableKeys reads the key names of the tables from the sonic-yang models
+// in dir. Table T of module sonic-x is container sonic-x/T, and each list of
+// it gives the names of one key of the table, in table key order.
+func loadTableKeys(dir string) (map[string][][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "sonic-*.yang"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("No sonic-yang models found in %v", dir)
+	}
+	keys := map[string][][]string{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		stmts, err := yang.Parse(string(data), file)
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range stmts {
+			node, err := yang.BuildAST(stmt)
+			if err != nil {
+				return nil, err
+			}
+			module, ok := node.(*yang.Module)
+			if !ok {
+				continue
+			}
+			for _, top := range module.Container {
+				for _, table := range top.Container {
+					for _, list := range table.List {
+						if list.Key != nil {
+							keys[table.Name] = append(keys[table.Name], strings.Fields(list.Key.Name))
+						}
+					}
+				}
+			}
+		}
+	}
+	return keys, nil
+}
+
+// tableKeyNames returns the names of the key of table that are exactly the
+// names given, in table key order.
+func tableKeyNames(table string, given map[string]string) ([]string, error) {
+	tableKeysOnce.Do(func() {
+		tableKeys, tableKeysErr = loadTableKeys(sonicYangModelsDir)
+		if tableKeysErr != nil {
+			log.Errorf("Failed to load the table keys: %v", tableKeysErr)
+		}
+	})
+	if tableKeysErr != nil {
+		return nil, fmt.Errorf("Key order of %v is not known: %v", table, tableKeysErr)
+	}
+	for _, names := range tableKeys[table] {
+		if len(names) != len(given) {
+			continue
+		}
+		found := true
+		for _, name := range names {
+			if _, ok := given[name]; !ok {
+				found = false
+				break
+			}
+		}
+		if found {
+			return names, nil
+		}
+	}
+	return nil, fmt.Errorf("No key of %v has the key names given", table)
+}
+
+// pathElemNames returns the DB path components of a gNMI path element: its
+// name, followed by the table key given by its keys, as in PORT[name=Ethernet0].
+// gNMI keys are unordered, so several keys are joined with separator in the
+// key order of the sonic-yang list of the table, as in
+// VLAN_MEMBER[name=Vlan100][port=Ethernet0] for Vlan100|Ethernet0. A single
+// key holding the joined table key, as in VLAN_MEMBER[name=Vlan100|Ethernet0],
+// is taken as is.
+func pathElemNames(elem *gnmipb.PathElem, separator string) ([]string, error) {
+	names := []string{elem.GetName()}
+	keys := elem.GetKey()
+	switch len(keys) {
+	case 0:
+	case 1:
+		for _, key := range keys {
+			names = append(names, key)
+		}
+	default:
+		keyNames, err := tableKeyNames(elem.GetName(), keys)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, len(keyNames))
+		for i, name := range keyNames {
+			values[i] = keys[name]
+		}
+		names = append(names, strings.Join(values, separator))
+	}
+	return names, nil
+}
+
 func populateAllDbtablePath(prefix *gnmipb.Path, paths []*gnmipb.Path, pathG2S *map[*gnmipb.Path][]tablePath) error {
 	for _, path := range paths {
 		err := populateDbtablePath(prefix, path, pathG2S)
@@ -622,13 +860,18 @@ func populateDbtablePath(prefix, path *gnmipb.Path, pathG2S *map[*gnmipb.Path][]
 	elems := fullPath.GetElem()
 	if elems != nil {
 		for i, elem := range elems {
-			// TODO: Usage of key field
 			log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-			if i != 0 {
-				buffer.WriteString(separator)
+			names, err := pathElemNames(elem, separator)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				if buffer.Len() != 0 {
+					buffer.WriteString(separator)
+				}
+				buffer.WriteString(name)
+				stringSlice = append(stringSlice, name)
 			}
-			buffer.WriteString(elem.GetName())
-			stringSlice = append(stringSlice, elem.GetName())
 		}
 		dbPath = buffer.String()
 	}
@@ -916,8 +1159,12 @@ func putFatalMsg(q *queue.PriorityQueue, msg string) {
 // It handles queries like "COUNTERS/Ethernet*/xyz" where the path translates to a field  in multiple tables.
 // For SAMPLE mode, it would send periodically regardless of change.
 // However, if `updateOnly` is true, the payload would include only the changed fields.
-// For ON_CHANGE mode, it would send only if the value has changed since the last update.
-func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration, updateOnly bool) {
+// If `suppressRedundant` is true, samples without any change are not sent.
+// For ON_CHANGE mode, it would send only the fields which have changed, when the
+// keyspace notifications of their tables signal a change.
+// All the fields are sent every `heartbeat`, if not zero.
+func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration, updateOnly bool,
+	heartbeat time.Duration, suppressRedundant bool) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
@@ -925,7 +1172,7 @@ func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, in
 	// Init the path to value map, it saves the previous value
 	path2ValueMap := make(map[tablePath]string)
 
-	readVal := func() map[string]interface{} {
+	readVal := func(tblPaths []tablePath) map[string]interface{} {
 		msi := make(map[string]interface{})
 		for _, tblPath := range tblPaths {
 			var key string
@@ -986,38 +1233,118 @@ func dbFieldMultiSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, in
 		return nil
 	}
 
-	msi := readVal()
+	// Subscribe before the initial read, not to miss any change.
+	// Table paths are grouped by key, for each DB.
+	notify := make(chan struct{}, 1)
+	listenerPaths := make(map[*keyspaceListener]map[string][]tablePath)
+	if onChange {
+		dbPaths := make(map[*redis.Client]map[string][]tablePath)
+		dbNames := make(map[*redis.Client]string)
+		for _, tblPath := range tblPaths {
+			redisDb := Target2RedisDb[tblPath.dbNamespace][tblPath.dbName]
+			if _, ok := dbPaths[redisDb]; !ok {
+				dbPaths[redisDb] = make(map[string][]tablePath)
+				dbNames[redisDb] = tblPath.dbName
+			}
+			key := tblPath.tableName
+			if tblPath.tableKey != "" {
+				key += tblPath.delimitor + tblPath.tableKey
+			}
+			dbPaths[redisDb][key] = append(dbPaths[redisDb][key], tblPath)
+		}
+		for redisDb, keyPaths := range dbPaths {
+			keys := make([]string, 0, len(keyPaths))
+			for key := range keyPaths {
+				keys = append(keys, key)
+			}
+			listener, err := subscribeKeyspace(redisDb, dbNames[redisDb], keys, notify)
+			if err != nil {
+				enqueueFatalMsg(c, err.Error())
+				c.synced.Done()
+				return
+			}
+			defer listener.close()
+			listenerPaths[listener] = keyPaths
+		}
+	}
+
+	msi := readVal(tblPaths)
 	if err := sendVal(msi); err != nil {
 		c.synced.Done()
 		return
 	}
 	c.synced.Done()
+	lastMsi := msi
 
-	intervalTicker := GetIntervalTicker()(interval)
+	// Nil tickers never tick: no interval in ON_CHANGE mode, no disabled heartbeat
+	var intervalTicker, heartbeatTicker <-chan time.Time
+	if !onChange {
+		intervalTicker = GetIntervalTicker()(interval)
+	}
+	if heartbeat > 0 {
+		heartbeatTicker = GetIntervalTicker()(heartbeat)
+	}
 	for {
 		select {
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbFieldMultiSubscribe routine for Client %s ", c)
 			return
-		case <-intervalTicker:
-			msi := readVal()
+		case <-notify:
+			var changedPaths []tablePath
+			for listener, keyPaths := range listenerPaths {
+				for _, key := range listener.changedKeys() {
+					changedPaths = append(changedPaths, keyPaths[key]...)
+				}
+			}
+			msi := readVal(changedPaths)
 
-			if onChange == false || len(msi) != 0 {
+			if len(msi) != 0 {
 				if err := sendVal(msi); err != nil {
 					log.Errorf("Queue error:  %v", err)
 					return
 				}
 			}
+		case <-intervalTicker:
+			intervalTicker = GetIntervalTicker()(interval)
+			msi := readVal(tblPaths)
+			if suppressRedundant && (len(msi) == 0 || reflect.DeepEqual(msi, lastMsi)) {
+				log.V(6).Infof("Redundant sample of %v suppressed", gnmiPath)
+				continue
+			}
+
+			if err := sendVal(msi); err != nil {
+				log.Errorf("Queue error:  %v", err)
+				return
+			}
+			lastMsi = msi
+		case <-heartbeatTicker:
+			heartbeatTicker = GetIntervalTicker()(heartbeat)
+			// Send the last values of all the fields, changed or not
+			readVal(tblPaths)
+			msi := make(map[string]interface{})
+			for _, tblPath := range tblPaths {
+				if val, ok := path2ValueMap[tblPath]; ok {
+					msi[tblPath.jsonTableKey] = map[string]string{tblPath.jsonField: val}
+				}
+			}
+
+			if err := sendVal(msi); err != nil {
+				log.Errorf("Queue error:  %v", err)
+				return
+			}
+			lastMsi = msi
 		}
-		intervalTicker = GetIntervalTicker()(interval)
 	}
 }
 
 // dbFieldSubscribe would read a field from a single table and put to output queue.
 // Handles queries like "COUNTERS/Ethernet0/xyz" where the path translates to a field in a table.
-// For SAMPLE mode, it would send periodically regardless of change.
-// For ON_CHANGE mode, it would send only if the value has changed since the last update.
-func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration) {
+// For SAMPLE mode, it would send periodically regardless of change, unless `suppressRedundant` is true.
+// For ON_CHANGE mode, it would send only if the value has changed since the last update,
+// checked when a keyspace notification signals a change of the table.
+// The value is sent every `heartbeat` regardless of change, if not zero.
+func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interval time.Duration,
+	heartbeat time.Duration, suppressRedundant bool) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
@@ -1065,6 +1392,18 @@ func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interva
 		return nil
 	}
 
+	// Subscribe before the initial read, not to miss any change
+	notify := make(chan struct{}, 1)
+	if onChange {
+		listener, err := subscribeKeyspace(redisDb, tblPath.dbName, []string{key}, notify)
+		if err != nil {
+			putFatalMsg(c.q, err.Error())
+			c.synced.Done()
+			return
+		}
+		defer listener.close()
+	}
+
 	// Read the initial value and signal sync after sending it
 	val := readVal()
 	err := sendVal(val)
@@ -1075,24 +1414,51 @@ func dbFieldSubscribe(c *DbClient, gnmiPath *gnmipb.Path, onChange bool, interva
 	}
 	c.synced.Done()
 
-	intervalTicker := GetIntervalTicker()(interval)
+	// Nil tickers never tick: no interval in ON_CHANGE mode, no disabled heartbeat
+	var intervalTicker, heartbeatTicker <-chan time.Time
+	if !onChange {
+		intervalTicker = GetIntervalTicker()(interval)
+	}
+	if heartbeat > 0 {
+		heartbeatTicker = GetIntervalTicker()(heartbeat)
+	}
 	for {
 		select {
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbFieldSubscribe routine for Client %s ", c)
 			return
-		case <-intervalTicker:
+		case <-notify:
+			// Only the subscribed field matters, not the other fields of the table
 			newVal := readVal()
 
-			if onChange == false || newVal != val {
+			if newVal != val {
 				if err = sendVal(newVal); err != nil {
 					log.V(1).Infof("Queue error:  %v", err)
 					return
 				}
 				val = newVal
 			}
+		case <-intervalTicker:
+			intervalTicker = GetIntervalTicker()(interval)
+			newVal := readVal()
+			if suppressRedundant && newVal == val {
+				log.V(6).Infof("Redundant sample of %v suppressed", gnmiPath)
+				continue
+			}
+
+			if err = sendVal(newVal); err != nil {
+				log.V(1).Infof("Queue error:  %v", err)
+				return
+			}
+			val = newVal
+		case <-heartbeatTicker:
+			heartbeatTicker = GetIntervalTicker()(heartbeat)
+			val = readVal()
+			if err = sendVal(val); err != nil {
+				log.V(1).Infof("Queue error:  %v", err)
+				return
+			}
 		}
-		intervalTicker = GetIntervalTicker()(interval)
 	}
 }
 
@@ -1192,7 +1558,10 @@ func dbSingleTableKeySubscribe(c *DbClient, rsd redisSubData, updateChannel chan
 // dbTableKeySubscribe subscribes to tables using a table keys.
 // Handles queries like "COUNTERS/Ethernet0" or "COUNTERS/Ethernet*"
 // This function handles both ON_CHANGE and SAMPLE modes. "interval" being 0 is interpreted as ON_CHANGE mode.
-func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Duration, updateOnly bool) {
+// In SAMPLE mode, samples without any update are not sent if "suppressRedundant" is true.
+// The whole data is sent every "heartbeat", if not zero.
+func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Duration, updateOnly bool,
+	heartbeat time.Duration, suppressRedundant bool) {
 	defer c.w.Done()
 
 	tblPaths := c.pathG2S[gnmiPath]
@@ -1312,18 +1681,21 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 
 	// Listen on updates from tables.
 	// Depending on the interval, send the updates every interval or on change only.
-	intervalTicker := make(<-chan time.Time)
+	// The interval ticker ticks only when the interval is non-zero.
+	// Otherwise (e.g. on-change mode) it would never tick, as the disabled heartbeat.
+	var intervalTicker, heartbeatTicker <-chan time.Time
+	if interval > 0 {
+		intervalTicker = GetIntervalTicker()(interval)
+	}
+	if heartbeat > 0 {
+		heartbeatTicker = GetIntervalTicker()(heartbeat)
+	}
+	updated := false
 	for {
-
-		// The interval ticker ticks only when the interval is non-zero.
-		// Otherwise (e.g. on-change mode) it would never tick.
-		if interval > 0 {
-			intervalTicker = GetIntervalTicker()(interval)
-		}
-
 		select {
 		case updatedTable := <-updateChannel:
 			log.V(6).Infof("update received: %v", updatedTable)
+			updated = true
 			if interval == 0 {
 				// on-change mode, send the updated data.
 				if err := sendMsiData(updatedTable); err != nil {
@@ -1337,12 +1709,18 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 				}
 			}
 		case <-intervalTicker:
+			intervalTicker = GetIntervalTicker()(interval)
 			log.V(6).Infof("ticker received: %v", len(msiAll))
+			if suppressRedundant && !updated {
+				log.V(6).Infof("Redundant sample of %v suppressed", gnmiPath)
+				continue
+			}
 
 			if err := sendMsiData(msiAll); err != nil {
 				handleFatalMsg(err.Error())
 				return
 			}
+			updated = false
 
 			// Clear the payload so that next time it will send only updates
 			if updateOnly {
@@ -1350,6 +1728,21 @@ func dbTableKeySubscribe(c *DbClient, gnmiPath *gnmipb.Path, interval time.Durat
 				log.V(6).Infof("msiAll cleared: %v", len(msiAll))
 			}
 
+		case <-heartbeatTicker:
+			heartbeatTicker = GetIntervalTicker()(heartbeat)
+			// Send the whole data, updated or not
+			msiData := make(map[string]interface{})
+			for _, tblPath := range tblPaths {
+				if err := TableData2Msi(&tblPath, false, nil, &msiData); err != nil {
+					handleFatalMsg(err.Error())
+					return
+				}
+			}
+			if err := sendMsiData(msiData); err != nil {
+				handleFatalMsg(err.Error())
+				return
+			}
+
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbTableKeySubscribe routine for %v ", c.pathG2S)
 			return
@@ -1371,6 +1764,16 @@ func (c *DbClient) SentOne(val *Value) {
 func (c *DbClient) FailedSend() {
 }
 
+// validateHeartbeatInterval validates the heartbeat interval of the given subscription.
+// A zero interval disables heartbeats.
+func validateHeartbeatInterval(sub *gnmipb.Subscription) (time.Duration, error) {
+	heartbeatInterval := time.Duration(sub.GetHeartbeatInterval())
+	if heartbeatInterval != 0 && heartbeatInterval < MinSampleInterval {
+		return 0, fmt.Errorf("invalid heartbeat interval: %v. It cannot be less than %v", heartbeatInterval, MinSampleInterval)
+	}
+	return heartbeatInterval, nil
+}
+
 // validateSampleInterval validates the sampling interval of the given subscription.
 func validateSampleInterval(sub *gnmipb.Subscription) (time.Duration, error) {
 	requestedInterval := time.Duration(sub.GetSampleInterval())
@@ -74,6 +74,15 @@ type EventClient struct {
     stopped     int
     stopMutex   sync.RWMutex
 
+    // Stop receiving once no event is pending, for ONCE subscriptions
+    stopOnIdle  bool
+
+    // Events received since the last poll, for POLL subscriptions.
+    // Bounded by pq_max, as the priority queue is for STREAM ones.
+    polling     bool
+    pollCache   []*spb.Value
+    pollMutex   sync.Mutex
+
     // Stats counter
     counters    map[string]uint64
     countersMutex sync.RWMutex
@@ -339,6 +348,10 @@ func get_events(evtc *EventClient) {
 
         rc, evt := C_recv_evt(evtc.subs_handle)
 
+        if rc != 0 && evtc.stopOnIdle {
+            log.V(4).Infof("%v no more pending events", evtc)
+            break
+        }
         if rc == 0 {
             evtc.countersMutex.Lock()
             current_missed_cnt := evtc.counters[MISSED]
@@ -349,7 +362,7 @@ func get_events(evtc *EventClient) {
             evtc.countersMutex.RUnlock()
 
             if !strings.HasPrefix(evt.Event_str, TEST_EVENT) {
-                qlen := evtc.q.Len()
+                qlen := evtc.pendingLen()
 
                 if (qlen < evtc.pq_max) {
                     var fvp map[string]interface{}
@@ -403,6 +416,12 @@ func send_event(evtc *EventClient, tv *gnmipb.TypedValue,
         Val:  tv,
     }
 
+    if evtc.polling {
+        evtc.pollMutex.Lock()
+        evtc.pollCache = append(evtc.pollCache, spbv)
+        evtc.pollMutex.Unlock()
+        return nil
+    }
     if err := evtc.q.Put(Value{spbv}); err != nil {
         log.V(3).Infof("Queue error:  %v", err)
         return err
@@ -410,6 +429,17 @@ func send_event(evtc *EventClient, tv *gnmipb.TypedValue,
     return nil
 }
 
+// pendingLen returns the number of events not sent yet: queued, or cached
+// until the next poll.
+func (evtc *EventClient) pendingLen() int {
+    if evtc.polling {
+        evtc.pollMutex.Lock()
+        defer evtc.pollMutex.Unlock()
+        return len(evtc.pollCache)
+    }
+    return evtc.q.Len()
+}
+
 func (evtc *EventClient) StreamRun(q *queue.PriorityQueue, stop chan struct{}, wg *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
 
     evtc.wg = wg
@@ -447,12 +477,87 @@ func (evtc *EventClient) Get(wg *sync.WaitGroup) ([]*spb.Value, error) {
     return nil, nil
 }
 
+// OnceRun sends the events pending for the subscriber, including the cached
+// ones, until none is received within the subscriber timeout. The sync
+// response follows.
 func (evtc *EventClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, wg *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-    return
+    evtc.wg = wg
+    defer evtc.wg.Done()
+
+    evtc.q = q
+    evtc.channel = once
+
+    if _, more := <-evtc.channel; !more {
+        log.V(1).Infof("%v once channel closed, exiting onceEvents routine", evtc)
+        C_deinit_subs(evtc.subs_handle)
+        evtc.subs_handle = nil
+        return
+    }
+
+    t1 := time.Now()
+    evtc.stopOnIdle = true
+    evtc.wg.Add(1)
+    get_events(evtc)
+
+    evtc.q.Put(Value{
+        &spb.Value{
+            Timestamp:    time.Now().UnixNano(),
+            SyncResponse: true,
+        },
+    })
+    log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 
+// PollRun receives the events in the background, and sends the ones cached
+// since the previous poll on each poll. Events are dropped while the cache
+// is full.
 func (evtc *EventClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, wg *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-    return
+    evtc.wg = wg
+    defer evtc.wg.Done()
+
+    evtc.q = q
+    evtc.channel = poll
+    evtc.polling = true
+
+    // get_events and update_stats run until stopped is set
+    defer func() {
+        evtc.stopMutex.Lock()
+        evtc.stopped = 1
+        evtc.stopMutex.Unlock()
+    }()
+
+    evtc.wg.Add(2)
+    go get_events(evtc)
+    go update_stats(evtc)
+
+    for {
+        if _, more := <-evtc.channel; !more {
+            log.V(1).Infof("%v poll channel closed, exiting pollEvents routine", evtc)
+            return
+        }
+        t1 := time.Now()
+        evtc.pollMutex.Lock()
+        cached := evtc.pollCache
+        evtc.pollCache = nil
+        evtc.pollMutex.Unlock()
+
+        for _, spbv := range cached {
+            if err := evtc.q.Put(Value{spbv}); err != nil {
+                log.V(3).Infof("Queue error:  %v", err)
+                return
+            }
+        }
+        if err := evtc.q.Put(Value{
+            &spb.Value{
+                Timestamp:    time.Now().UnixNano(),
+                SyncResponse: true,
+            },
+        }); err != nil {
+            log.V(3).Infof("Queue error:  %v", err)
+            return
+        }
+        log.V(4).Infof("Sync done, %d events polled in %v ms", len(cached), int64(time.Since(t1)/time.Millisecond))
+    }
 }
 
 
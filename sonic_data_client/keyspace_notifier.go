@@ -0,0 +1,219 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	log "github.com/golang/glog"
+	spb "github.com/sonic-net/sonic-gnmi/proto"
+)
+
+// Maximum wait for redis to confirm a keyspace subscription.
+var keyspaceSubscribeTimeout = time.Second
+
+// keyspaceNotifier dispatches the keyspace notifications of a DB to the
+// listeners of all clients, over a single pubsub connection.
+type keyspaceNotifier struct {
+	redisDb *redis.Client
+	pubsub  *redis.PubSub
+
+	mu       sync.Mutex
+	closed   bool
+	channels map[string]*keyspaceChannel
+}
+
+// keyspaceChannel holds the listeners of the notifications of a key.
+type keyspaceChannel struct {
+	listeners map[*keyspaceListener]struct{}
+	confirmed chan struct{} // closed when redis confirms the subscription
+}
+
+// keyspaceListener collects the changed keys of a subscription until it
+// reads them, so that a slow client never blocks the notifier.
+type keyspaceListener struct {
+	notifier *keyspaceNotifier
+	keys     map[string]string // key by keyspace channel
+	notify   chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	pending map[string]struct{}
+}
+
+var keyspaceNotifiersMu sync.Mutex
+
+// Notifiers by redis client of their DB
+var keyspaceNotifiers = make(map[*redis.Client]*keyspaceNotifier)
+
+// subscribeKeyspace returns a listener of the changes of keys in the DB
+// dbName of redisDb. Each change is signaled on notify, which should be
+// buffered as signals are dropped while one is pending.
+func subscribeKeyspace(redisDb *redis.Client, dbName string, keys []string, notify chan struct{}) (*keyspaceListener, error) {
+	prefix := "__keyspace@" + strconv.Itoa(int(spb.Target_value[dbName])) + "__:"
+	l := &keyspaceListener{
+		keys:    make(map[string]string),
+		notify:  notify,
+		pending: make(map[string]struct{}),
+	}
+
+	keyspaceNotifiersMu.Lock()
+	n, ok := keyspaceNotifiers[redisDb]
+	if !ok {
+		n = &keyspaceNotifier{
+			redisDb:  redisDb,
+			pubsub:   redisDb.Subscribe(),
+			channels: make(map[string]*keyspaceChannel),
+		}
+		keyspaceNotifiers[redisDb] = n
+		go n.run()
+	}
+	l.notifier = n
+
+	n.mu.Lock()
+	var newChannels []string
+	var confirmations []chan struct{}
+	for _, key := range keys {
+		channel := prefix + key
+		l.keys[channel] = key
+		ch, ok := n.channels[channel]
+		if !ok {
+			ch = &keyspaceChannel{
+				listeners: make(map[*keyspaceListener]struct{}),
+				confirmed: make(chan struct{}),
+			}
+			n.channels[channel] = ch
+			newChannels = append(newChannels, channel)
+		}
+		ch.listeners[l] = struct{}{}
+		confirmations = append(confirmations, ch.confirmed)
+	}
+	var err error
+	if len(newChannels) != 0 {
+		err = n.pubsub.Subscribe(newChannels...)
+	}
+	n.mu.Unlock()
+	keyspaceNotifiersMu.Unlock()
+
+	if err != nil {
+		l.close()
+		return nil, fmt.Errorf("subscribe to keyspace of %v failed: %v", keys, err)
+	}
+	timeout := time.After(keyspaceSubscribeTimeout)
+	for _, confirmed := range confirmations {
+		select {
+		case <-confirmed:
+		case <-timeout:
+			l.close()
+			return nil, fmt.Errorf("subscribe to keyspace of %v timed out", keys)
+		}
+	}
+	log.V(2).Infof("Subscribed to keyspace of %v in %v", keys, dbName)
+	return l, nil
+}
+
+func (n *keyspaceNotifier) run() {
+	for {
+		msgi, err := n.pubsub.Receive()
+
+		n.mu.Lock()
+		if n.closed {
+			n.mu.Unlock()
+			return
+		}
+		if err != nil {
+			// The connection is restored on the next receive, but notifications
+			// may have been lost meanwhile. Have the listeners read all their keys.
+			log.V(2).Infof("Keyspace notification receive error: %v", err)
+			for channel, ch := range n.channels {
+				for l := range ch.listeners {
+					l.push(channel)
+				}
+			}
+			n.mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		switch msg := msgi.(type) {
+		case *redis.Subscription:
+			if ch, ok := n.channels[msg.Channel]; ok && msg.Kind == "subscribe" {
+				select {
+				case <-ch.confirmed:
+				default:
+					close(ch.confirmed)
+				}
+			}
+		case *redis.Message:
+			if ch, ok := n.channels[msg.Channel]; ok {
+				for l := range ch.listeners {
+					l.push(msg.Channel)
+				}
+			}
+		}
+		n.mu.Unlock()
+	}
+}
+
+func (l *keyspaceListener) push(channel string) {
+	l.mu.Lock()
+	l.pending[l.keys[channel]] = struct{}{}
+	l.mu.Unlock()
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
+
+// changedKeys returns the keys changed since the previous call.
+func (l *keyspaceListener) changedKeys() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := make([]string, 0, len(l.pending))
+	for key := range l.pending {
+		keys = append(keys, key)
+	}
+	l.pending = make(map[string]struct{})
+	return keys
+}
+
+// close unsubscribes the listener. The pubsub connection is closed with the
+// last listener of the DB.
+func (l *keyspaceListener) close() {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	n := l.notifier
+	keyspaceNotifiersMu.Lock()
+	defer keyspaceNotifiersMu.Unlock()
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var unused []string
+	for channel := range l.keys {
+		ch, ok := n.channels[channel]
+		if !ok {
+			continue
+		}
+		delete(ch.listeners, l)
+		if len(ch.listeners) == 0 {
+			delete(n.channels, channel)
+			unused = append(unused, channel)
+		}
+	}
+	if len(n.channels) == 0 {
+		n.closed = true
+		n.pubsub.Close()
+		delete(keyspaceNotifiers, n.redisDb)
+	} else if len(unused) != 0 {
+		if err := n.pubsub.Unsubscribe(unused...); err != nil {
+			log.V(2).Infof("Unsubscribe from %v failed: %v", unused, err)
+		}
+	}
+}
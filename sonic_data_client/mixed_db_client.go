@@ -68,6 +68,8 @@ type MixedDbClient struct {
 	paths   []*gnmipb.Path
 	pathG2S map[*gnmipb.Path][]tablePath
 	encoding gnmipb.Encoding
+	// dataType limits Get to the data of one gNMI GetRequest type
+	dataType gnmipb.GetRequest_DataType
 	q       *queue.PriorityQueue
 	channel chan struct{}
 	target  string
@@ -493,7 +495,7 @@ func init() {
 	initRedisDbMap()
 }
 
-func NewMixedDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, origin string, encoding gnmipb.Encoding, zmqPort string) (Client, error) {
+func NewMixedDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, origin string, encoding gnmipb.Encoding, zmqPort string, opts ...ClientOption) (Client, error) {
 	var err error
 
 	// Initialize RedisDbMap for test
@@ -523,6 +525,7 @@ func NewMixedDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, origin string,
 	client.target = ""
 	client.origin = origin
 	client.encoding = encoding
+	client.dataType = getDataType(opts)
 	if prefix != nil {
 		elems := prefix.GetElem()
 		if elems != nil {
@@ -619,15 +622,16 @@ func (c *MixedDbClient) getDbtablePath(path *gnmipb.Path, value *gnmipb.TypedVal
 	if elems != nil {
 		for i, elem := range elems {
 			log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-			if i != 0 {
-				buffer.WriteString(separator)
+			names, err := pathElemNames(elem, separator)
+			if err != nil {
+				return nil, err
 			}
-			buffer.WriteString(elem.GetName())
-			stringSlice = append(stringSlice, elem.GetName())
-			value, ok := elem.GetKey()["key"]
-			if ok {
-				buffer.WriteString(value)
-				stringSlice = append(stringSlice, value)
+			for _, name := range names {
+				if buffer.Len() != 0 {
+					buffer.WriteString(separator)
+				}
+				buffer.WriteString(name)
+				stringSlice = append(stringSlice, name)
 			}
 		}
 		dbPath = buffer.String()
@@ -1127,6 +1131,9 @@ func (c *MixedDbClient) handleTableData(tblPaths []tablePath) error {
 }
 
 /* Populate the JsonPatch corresponding each GNMI operation. */
+// Escapes a JSON pointer reference token, see RFC 6901.
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 func (c *MixedDbClient) ConvertToJsonPatch(prefix *gnmipb.Path, path *gnmipb.Path, t *gnmipb.TypedValue, operation string, output *map[string]interface{}) error {
 	if t != nil {
 		if len(t.GetJsonIetfVal()) == 0 {
@@ -1141,25 +1148,20 @@ func (c *MixedDbClient) ConvertToJsonPatch(prefix *gnmipb.Path, path *gnmipb.Pat
 	elems := fullPath.GetElem()
 	(*output)["op"] = operation
 	jsonPath := "/"
+	separator, _ := GetTableKeySeparatorByDBKey(c.target, c.dbkey)
 
 	if elems != nil {
 		/* Iterate through elements. */
 		for _, elem := range elems {
-			jsonPath += elem.GetName()
-			key := elem.GetKey()
-			/* If no keys are present end the element with "/" */
-			if key == nil {
-				jsonPath += `/`
+			names, err := pathElemNames(elem, separator)
+			if err != nil {
+				return err
 			}
+			jsonPath += names[0] + `/`
 
-			/* If keys are present , process the keys. */
-			if key != nil {
-				for k, v := range key {
-					jsonPath += `[` + k + `=` + v + `]`
-				}
-
-				/* Append "/" after all keys are processed. */
-				jsonPath += `/`
+			/* Names come escaped for JSON pointer, table keys do not. */
+			for _, key := range names[1:] {
+				jsonPath += jsonPointerEscaper.Replace(key) + `/`
 			}
 		}
 	}
@@ -1226,6 +1228,7 @@ func (c *MixedDbClient) SetIncrementalConfig(delete []*gnmipb.Path, replace []*g
 	if err != nil {
 		return err
 	}
+	separator, _ := GetTableKeySeparatorByDBKey(c.target, c.dbkey)
 
 	var patchList [](map[string]interface{})
 	/* DELETE */
@@ -1240,9 +1243,12 @@ func (c *MixedDbClient) SetIncrementalConfig(delete []*gnmipb.Path, replace []*g
 		elems := fullPath.GetElem()
 		if elems != nil {
 			for i, elem := range elems {
-				// TODO: Usage of key field
 				log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-				stringSlice = append(stringSlice, elem.GetName())
+				names, err := pathElemNames(elem, separator)
+				if err != nil {
+					return err
+				}
+				stringSlice = append(stringSlice, names...)
 			}
 			err := c.jClient.Remove(stringSlice)
 			if err != nil {
@@ -1270,9 +1276,12 @@ func (c *MixedDbClient) SetIncrementalConfig(delete []*gnmipb.Path, replace []*g
 		elems := fullPath.GetElem()
 		if elems != nil {
 			for i, elem := range elems {
-				// TODO: Usage of key field
 				log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-				stringSlice = append(stringSlice, elem.GetName())
+				names, err := pathElemNames(elem, separator)
+				if err != nil {
+					return err
+				}
+				stringSlice = append(stringSlice, names...)
 			}
 			t := path.GetVal()
 			if t == nil {
@@ -1309,9 +1318,12 @@ func (c *MixedDbClient) SetIncrementalConfig(delete []*gnmipb.Path, replace []*g
 		elems := fullPath.GetElem()
 		if elems != nil {
 			for i, elem := range elems {
-				// TODO: Usage of key field
 				log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-				stringSlice = append(stringSlice, elem.GetName())
+				names, err := pathElemNames(elem, separator)
+				if err != nil {
+					return err
+				}
+				stringSlice = append(stringSlice, names...)
 			}
 			t := path.GetVal()
 			if t == nil {
@@ -1463,6 +1475,7 @@ func (c *MixedDbClient) GetCheckPoint() ([]*spb.Value, error) {
 	if err != nil {
 		return nil, fmt.Errorf("There's no check point")
 	}
+	separator, _ := GetTableKeySeparatorByDBKey(c.target, c.dbkey)
 	log.V(2).Infof("Getting #%v", c.jClient.jsonData)
 	for _, path := range c.paths {
 		fullPath, err := c.gnmiFullPath(c.prefix, path)
@@ -1475,9 +1488,12 @@ func (c *MixedDbClient) GetCheckPoint() ([]*spb.Value, error) {
 		elems := fullPath.GetElem()
 		if elems != nil {
 			for i, elem := range elems {
-				// TODO: Usage of key field
 				log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
-				stringSlice = append(stringSlice, elem.GetName())
+				names, err := pathElemNames(elem, separator)
+				if err != nil {
+					return nil, err
+				}
+				stringSlice = append(stringSlice, names...)
 			}
 			jv, err := c.jClient.Get(stringSlice)
 			if err != nil {
@@ -1500,6 +1516,10 @@ func (c *MixedDbClient) GetCheckPoint() ([]*spb.Value, error) {
 }
 
 func (c *MixedDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
+	if !IsDbOfDataType(c.target, c.dataType) {
+		log.V(4).Infof("%v holds no %v data", c.target, c.dataType)
+		return nil, nil
+	}
 	if c.target == "CONFIG_DB" {
 		ret, err := c.GetCheckPoint()
 		if err == nil {
@@ -1508,25 +1528,10 @@ func (c *MixedDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 		log.V(6).Infof("Error #%v", err)
 	}
 
-	var values []*spb.Value
 	ts := time.Now()
-	if c.paths != nil {
-		for _, gnmiPath := range c.paths {
-			tblPaths, err := c.getDbtablePath(gnmiPath, nil)
-			if err != nil {
-				return nil, err
-			}
-			val, err := c.tableData2TypedValue(tblPaths, nil)
-			if err != nil {
-				return nil, err
-			}
-			values = append(values, &spb.Value{
-				Prefix:    c.prefix,
-				Path:      gnmiPath,
-				Timestamp: ts.UnixNano(),
-				Val:       val,
-			})
-		}
+	values, err := c.readPaths()
+	if err != nil {
+		return nil, err
 	}
 
 	log.V(6).Infof("Getting #%v", values)
@@ -1534,8 +1539,61 @@ func (c *MixedDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	return values, nil
 }
 
+// readPaths reads the values of the paths of the client, for Get and for
+// ONCE and POLL subscriptions alike.
+func (c *MixedDbClient) readPaths() ([]*spb.Value, error) {
+	var values []*spb.Value
+	ts := time.Now()
+	for _, gnmiPath := range c.paths {
+		tblPaths, err := c.getDbtablePath(gnmiPath, nil)
+		if err != nil {
+			log.V(2).Infof("Unable to get table path due to err: %v", err)
+			return nil, err
+		}
+		val, err := c.tableData2TypedValue(tblPaths, nil)
+		if err != nil {
+			log.V(2).Infof("Unable to create gnmi TypedValue due to err: %v", err)
+			return nil, err
+		}
+		values = append(values, &spb.Value{
+			Prefix:    c.prefix,
+			Path:      gnmiPath,
+			Timestamp: ts.UnixNano(),
+			Val:       val,
+		})
+	}
+	return values, nil
+}
+
 func (c *MixedDbClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-	return
+	c.w = w
+	defer c.w.Done()
+	c.q = q
+	c.channel = once
+
+	_, more := <-c.channel
+	if !more {
+		log.V(1).Infof("%v once channel closed, exiting onceDb routine", c)
+		return
+	}
+	t1 := time.Now()
+	values, err := c.readPaths()
+	if err != nil {
+		putFatalMsg(c.q, err.Error())
+		return
+	}
+	for _, spbv := range values {
+		c.q.Put(Value{spbv})
+		log.V(6).Infof("Added spbv #%v", spbv)
+	}
+
+	c.q.Put(Value{
+		&spb.Value{
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: true,
+		},
+	})
+	log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 
 func (c *MixedDbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
@@ -1551,25 +1609,12 @@ func (c *MixedDbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *s
 			return
 		}
 		t1 := time.Now()
-		for _, gnmiPath := range c.paths {
-			tblPaths, err := c.getDbtablePath(gnmiPath, nil)
-			if err != nil {
-				log.V(2).Infof("Unable to get table path due to err: %v", err)
-				return
-			}
-			val, err := c.tableData2TypedValue(tblPaths, nil)
-			if err != nil {
-				log.V(2).Infof("Unable to create gnmi TypedValue due to err: %v", err)
-				return
-			}
-
-			spbv := &spb.Value{
-				Prefix:       c.prefix,
-				Path:         gnmiPath,
-				Timestamp:    time.Now().UnixNano(),
-				SyncResponse: false,
-				Val:          val,
-			}
+		values, err := c.readPaths()
+		if err != nil {
+			putFatalMsg(c.q, err.Error())
+			return
+		}
+		for _, spbv := range values {
 			c.q.Put(Value{spbv})
 			log.V(6).Infof("Added spbv #%v", spbv)
 		}
@@ -1653,7 +1698,8 @@ func (c *MixedDbClient) streamOnChangeSubscription(gnmiPath *gnmipb.Path) {
 	log.V(2).Infof("streamOnChangeSubscription gnmiPath: %v", gnmiPath)
 
 	if tblPaths[0].field != "" {
-		go c.dbFieldSubscribe(gnmiPath, true, time.Millisecond*200)
+		// sample interval is not applicable, changes come from keyspace notifications
+		go c.dbFieldSubscribe(gnmiPath, true, 0)
 	} else {
 		// sample interval and update only parameters are not applicable
 		go c.dbTableKeySubscribe(gnmiPath, 0, true)
@@ -1689,7 +1735,8 @@ func (c *MixedDbClient) streamSampleSubscription(sub *gnmipb.Subscription, updat
 // dbFieldSubscribe would read a field from a single table and put to output queue.
 // Handles queries like "COUNTERS/Ethernet0/xyz" where the path translates to a field in a table.
 // For SAMPLE mode, it would send periodically regardless of change.
-// For ON_CHANGE mode, it would send only if the value has changed since the last update.
+// For ON_CHANGE mode, it would send only if the value has changed since the last update,
+// checked when a keyspace notification signals a change of the table.
 func (c *MixedDbClient) dbFieldSubscribe(gnmiPath *gnmipb.Path, onChange bool, interval time.Duration) {
 	defer c.w.Done()
 
@@ -1749,6 +1796,18 @@ func (c *MixedDbClient) dbFieldSubscribe(gnmiPath *gnmipb.Path, onChange bool, i
 		return nil
 	}
 
+	// Subscribe before the initial read, not to miss any change
+	notify := make(chan struct{}, 1)
+	if onChange {
+		listener, err := subscribeKeyspace(redisDb, tblPath.dbName, []string{key}, notify)
+		if err != nil {
+			putFatalMsg(c.q, err.Error())
+			c.synced.Done()
+			return
+		}
+		defer listener.close()
+	}
+
 	// Read the initial value and signal sync after sending it
 	val := readVal()
 	err = sendVal(val)
@@ -1759,24 +1818,35 @@ func (c *MixedDbClient) dbFieldSubscribe(gnmiPath *gnmipb.Path, onChange bool, i
 	}
 	c.synced.Done()
 
-	intervalTicker := GetIntervalTicker()(interval)
+	// The ticker never ticks in ON_CHANGE mode
+	intervalTicker := make(<-chan time.Time)
 	for {
+		if !onChange {
+			intervalTicker = GetIntervalTicker()(interval)
+		}
+
 		select {
 		case <-c.channel:
 			log.V(1).Infof("Stopping dbFieldSubscribe routine for Client %s ", c)
 			return
-		case <-intervalTicker:
+		case <-notify:
+			// Only the subscribed field matters, not the other fields of the table
 			newVal := readVal()
 
-			if onChange == false || newVal != val {
+			if newVal != val {
 				if err = sendVal(newVal); err != nil {
 					log.V(1).Infof("Queue error:  %v", err)
 					return
 				}
 				val = newVal
 			}
+		case <-intervalTicker:
+			val = readVal()
+			if err = sendVal(val); err != nil {
+				log.V(1).Infof("Queue error:  %v", err)
+				return
+			}
 		}
-		intervalTicker = GetIntervalTicker()(interval)
 	}
 }
 
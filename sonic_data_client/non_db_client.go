@@ -364,9 +364,18 @@ func init() {
 	go PollStats()
 }
 
+// RegisterNonDbPath adds the getter of the data at path, path starting with
+// the OTHERS target. A getter already registered at path is replaced.
+func RegisterNonDbPath(path []string, getter func() ([]byte, error)) {
+	n := clientTrie.Add(path, dataGetFunc(getter))
+	log.V(2).Infof("Add trie node for %v with %v", path, n.meta)
+}
+
 type NonDbClient struct {
 	prefix      *gnmipb.Path
 	path2Getter map[*gnmipb.Path]dataGetFunc
+	// dataType limits Get to the data of one gNMI GetRequest type
+	dataType gnmipb.GetRequest_DataType
 
 	q       *queue.PriorityQueue
 	channel chan struct{}
@@ -387,8 +396,11 @@ func lookupGetFunc(prefix, path *gnmipb.Path) (dataGetFunc, error) {
 	elems := fullPath.GetElem()
 	if elems != nil {
 		for i, elem := range elems {
-			// TODO: Usage of key field
 			log.V(6).Infof("index %d elem : %#v %#v", i, elem.GetName(), elem.GetKey())
+			// Non-DB data has no table to key
+			if len(elem.GetKey()) != 0 {
+				return nil, fmt.Errorf("Keys of %v are not supported for %v", elem.GetName(), prefix.GetTarget())
+			}
 			stringSlice = append(stringSlice, elem.GetName())
 		}
 	}
@@ -400,10 +412,11 @@ func lookupGetFunc(prefix, path *gnmipb.Path) (dataGetFunc, error) {
 	return nil, fmt.Errorf("%v not found in clientTrie tree", stringSlice)
 }
 
-func NewNonDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path) (Client, error) {
+func NewNonDbClient(paths []*gnmipb.Path, prefix *gnmipb.Path, opts ...ClientOption) (Client, error) {
 	var ndc NonDbClient
 	ndc.path2Getter = make(map[*gnmipb.Path]dataGetFunc)
 	ndc.prefix = prefix
+	ndc.dataType = getDataType(opts)
 	for _, path := range paths {
 		getter, err := lookupGetFunc(prefix, path)
 		if err != nil {
@@ -554,7 +567,28 @@ func (c *NonDbClient) PollRun(q *queue.PriorityQueue, poll chan struct{}, w *syn
 	}
 }
 func (c *NonDbClient) OnceRun(q *queue.PriorityQueue, once chan struct{}, w *sync.WaitGroup, subscribe *gnmipb.SubscriptionList) {
-	return
+	c.w = w
+	defer c.w.Done()
+	c.q = q
+	c.channel = once
+
+	_, more := <-c.channel
+	if !more {
+		log.V(1).Infof("%v once channel closed, exiting onceDb routine", c)
+		return
+	}
+	t1 := time.Now()
+	for gnmiPath, getter := range c.path2Getter {
+		runGetterAndSend(c, gnmiPath, getter)
+	}
+
+	c.q.Put(Value{
+		&spb.Value{
+			Timestamp:    time.Now().UnixNano(),
+			SyncResponse: true,
+		},
+	})
+	log.V(4).Infof("Sync done, once time taken: %v ms", int64(time.Since(t1)/time.Millisecond))
 }
 func (c *NonDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	// wait sync for Get, not used for now
@@ -562,6 +596,10 @@ func (c *NonDbClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 
 	var values []*spb.Value
 	ts := time.Now()
+	if !IsDbOfDataType("OTHERS", c.dataType) {
+		log.V(4).Infof("OTHERS holds no %v data", c.dataType)
+		return values, nil
+	}
 	for gnmiPath, getter := range c.path2Getter {
 		v, err := getter()
 		if err != nil {
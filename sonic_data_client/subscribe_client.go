@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	gnmipb "github.com/openconfig/gnmi/proto/gnmi"
+	gnmi_extpb "github.com/openconfig/gnmi/proto/gnmi_ext"
+)
+
+// UnsupportedSubscribeError is returned by NewSubscribeClient when no data
+// client supports the origin or target of the subscription.
+type UnsupportedSubscribeError struct {
+	msg string
+}
+
+func (e *UnsupportedSubscribeError) Error() string {
+	return e.msg
+}
+
+// IsNativeOrigin reports whether origin addresses the SONiC databases natively.
+func IsNativeOrigin(origin string) bool {
+	return origin == "sonic-db"
+}
+
+// NewSubscribeClient returns the data client serving a subscription to paths
+// under prefix. The client is selected by origin, then by the prefix target
+// when no origin is given:
+//   - "openconfig" origin, or any target which is not a database: TranslClient
+//   - native "sonic-db" origin: MixedDbClient
+//   - "OTHERS" target: NonDbClient
+//   - "EVENTS" target: EventClient
+//   - database target: DbClient
+//
+// An UnsupportedSubscribeError is returned for unsupported origins and
+// targets; errors creating the client are returned as is.
+func NewSubscribeClient(ctx context.Context, prefix *gnmipb.Path, paths []*gnmipb.Path, origin string,
+	extensions []*gnmi_extpb.Extension, logLevel int) (Client, error) {
+	target := prefix.GetTarget()
+	if origin == "openconfig" {
+		return NewTranslClient(prefix, paths, ctx, extensions, TranslWildcardOption{})
+	} else if IsNativeOrigin(origin) {
+		return NewMixedDbClient(paths, prefix, origin, gnmipb.Encoding_JSON_IETF, "")
+	} else if len(origin) != 0 {
+		return nil, &UnsupportedSubscribeError{fmt.Sprintf("Unsupported origin: %s", origin)}
+	} else if target == "" {
+		// This and subsequent conditions handle target based path identification
+		// when origin == "". As per the spec it should have been treated as "openconfig".
+		// But we take a deviation and stick to legacy logic for backward compatibility
+		return nil, &UnsupportedSubscribeError{"Empty target data not supported"}
+	} else if target == "OTHERS" {
+		return NewNonDbClient(paths, prefix)
+	} else if target == "EVENTS" {
+		return NewEventClient(paths, prefix, logLevel)
+	} else if _, ok, _, _ := IsTargetDb(target); ok {
+		return NewDbClient(paths, prefix)
+	}
+	/* For any other target or no target create new Transl Client. */
+	return NewTranslClient(prefix, paths, ctx, extensions, TranslWildcardOption{})
+}
@@ -44,6 +44,7 @@ type TranslClient struct {
 
 	version  *translib.Version // Client version; populated by parseVersion()
 	encoding gnmipb.Encoding
+	dataType gnmipb.GetRequest_DataType // Get data type; populated by DataTypeOption
 }
 
 func NewTranslClient(prefix *gnmipb.Path, getpaths []*gnmipb.Path, ctx context.Context, extensions []*gnmi_extpb.Extension, opts ...TranslClientOption) (Client, error) {
@@ -56,8 +57,11 @@ func NewTranslClient(prefix *gnmipb.Path, getpaths []*gnmipb.Path, ctx context.C
 	if getpaths != nil {
 		var addWildcardKeys bool
 		for _, o := range opts {
-			if _, ok := o.(TranslWildcardOption); ok {
+			switch v := o.(type) {
+			case TranslWildcardOption:
 				addWildcardKeys = true
+			case DataTypeOption:
+				client.dataType = v.DataType
 			}
 		}
 
@@ -86,7 +90,7 @@ func (c *TranslClient) Get(w *sync.WaitGroup) ([]*spb.Value, error) {
 	/* Iterate through all GNMI paths. */
 	for gnmiPath, URIPath := range c.path2URI {
 		/* Fill values for each GNMI path. */
-		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx)
+		val, err := transutil.TranslProcessGet(URIPath, nil, c.ctx, c.dataType)
 
 		if err != nil {
 			return nil, err
@@ -1,6 +1,7 @@
 package host_service
 
 import (
+	"encoding/json"
 	"time"
 	"fmt"
 	"reflect"
@@ -18,6 +19,11 @@ type Service interface {
 	DeleteCheckPoint(cpName string) error
 	StopService(service string) error
 	RestartService(service string) error
+	Reboot(method int32, message string) error
+	KillProcess(container string, pid uint32, signal int) error
+	InstallImage(fileName string) error
+	ListImages() (string, error)
+	SetNextBoot(image string) error
 }
 
 type DbusClient struct {
@@ -40,12 +46,19 @@ func NewDbusClient() (Service, error) {
 }
 
 func DbusApi(busName string, busPath string, intName string, timeout int, args ...interface{}) error {
+	_, err := DbusApiOutput(busName, busPath, intName, timeout, args...)
+	return err
+}
+
+// DbusApiOutput calls the host service method like DbusApi, and returns the
+// output string which follows the return code, if any.
+func DbusApiOutput(busName string, busPath string, intName string, timeout int, args ...interface{}) (string, error) {
 	common_utils.IncCounter(common_utils.DBUS)
 	conn, err := dbus.SystemBus()
 	if err != nil {
 		log.V(2).Infof("Failed to connect to system bus: %v", err)
 		common_utils.IncCounter(common_utils.DBUS_FAIL)
-		return err
+		return "", err
 	}
 
 	ch := make(chan *dbus.Call, 1)
@@ -55,39 +68,44 @@ func DbusApi(busName string, busPath string, intName string, timeout int, args .
 	case call := <-ch:
 		if call.Err != nil {
 			common_utils.IncCounter(common_utils.DBUS_FAIL)
-			return call.Err
+			return "", call.Err
 		}
 		result := call.Body
 		if len(result) == 0 {
 			common_utils.IncCounter(common_utils.DBUS_FAIL)
-			return fmt.Errorf("Dbus result is empty %v", result)
+			return "", fmt.Errorf("Dbus result is empty %v", result)
 		}
 		if ret, ok := result[0].(int32); ok {
 			if ret == 0 {
-				return nil
+				if len(result) == 2 {
+					if msg, check := result[1].(string); check {
+						return msg, nil
+					}
+				}
+				return "", nil
 			} else {
 				if len(result) != 2 {
 					common_utils.IncCounter(common_utils.DBUS_FAIL)
-					return fmt.Errorf("Dbus result is invalid %v", result)
+					return "", fmt.Errorf("Dbus result is invalid %v", result)
 				}
 				if msg, check := result[1].(string); check {
 					common_utils.IncCounter(common_utils.DBUS_FAIL)
-					return fmt.Errorf(msg)
+					return "", fmt.Errorf(msg)
 				} else {
 					common_utils.IncCounter(common_utils.DBUS_FAIL)
-					return fmt.Errorf("Invalid result message type %v %v", result[1], reflect.TypeOf(result[1]))
+					return "", fmt.Errorf("Invalid result message type %v %v", result[1], reflect.TypeOf(result[1]))
 				}
 			}
 		} else {
 			common_utils.IncCounter(common_utils.DBUS_FAIL)
-			return fmt.Errorf("Invalid result type %v %v", result[0], reflect.TypeOf(result[0]))
+			return "", fmt.Errorf("Invalid result type %v %v", result[0], reflect.TypeOf(result[0]))
 		}
 	case <-time.After(time.Duration(timeout) * time.Second):
 		log.V(2).Infof("DbusApi: timeout")
 		common_utils.IncCounter(common_utils.DBUS_FAIL)
-		return fmt.Errorf("Timeout %v", timeout)
+		return "", fmt.Errorf("Timeout %v", timeout)
 	}
-	return nil
+	return "", nil
 }
 
 func (c *DbusClient) ConfigReload(config string) error {
@@ -169,3 +187,70 @@ func (c *DbusClient) RestartService(service string) error {
 	err := DbusApi(busName, busPath, intName, 90, service)
 	return err
 }
+
+// Reboot asks the host to reboot with a gNOI RebootMethod value, the host
+// service maps it to the matching reboot command.
+func (c *DbusClient) Reboot(method int32, message string) error {
+	common_utils.IncCounter(common_utils.DBUS_REBOOT)
+	modName := "gnoi_reboot"
+	busName := c.busNamePrefix + modName
+	busPath := c.busPathPrefix + modName
+	intName := c.intNamePrefix + modName + ".issue_reboot"
+	options, err := json.Marshal(map[string]interface{}{"method": method, "message": message})
+	if err != nil {
+		return err
+	}
+	err = DbusApi(busName, busPath, intName, 10, string(options))
+	return err
+}
+
+// KillProcess sends the Unix signal to the process pid of container, given
+// in the PID namespace of container, or to the main process of container if
+// pid is 0.
+func (c *DbusClient) KillProcess(container string, pid uint32, signal int) error {
+	common_utils.IncCounter(common_utils.DBUS_KILL_PROCESS)
+	modName := "process"
+	busName := c.busNamePrefix + modName
+	busPath := c.busPathPrefix + modName
+	intName := c.intNamePrefix + modName + ".kill_process"
+	options, err := json.Marshal(map[string]interface{}{"container": container, "pid": pid, "signal": signal})
+	if err != nil {
+		return err
+	}
+	err = DbusApi(busName, busPath, intName, 10, string(options))
+	return err
+}
+
+// InstallImage installs the SONiC image file, given by its path on the
+// host.
+func (c *DbusClient) InstallImage(fileName string) error {
+	common_utils.IncCounter(common_utils.DBUS_INSTALL_IMAGE)
+	modName := "image_service"
+	busName := c.busNamePrefix + modName
+	busPath := c.busPathPrefix + modName
+	intName := c.intNamePrefix + modName + ".install"
+	err := DbusApi(busName, busPath, intName, 900, fileName)
+	return err
+}
+
+// ListImages returns the installed SONiC images as a JSON object with
+// "current", "next" and "available" fields.
+func (c *DbusClient) ListImages() (string, error) {
+	common_utils.IncCounter(common_utils.DBUS_LIST_IMAGES)
+	modName := "image_service"
+	busName := c.busNamePrefix + modName
+	busPath := c.busPathPrefix + modName
+	intName := c.intNamePrefix + modName + ".list_images"
+	return DbusApiOutput(busName, busPath, intName, 60)
+}
+
+// SetNextBoot sets the installed SONiC image booted by the next reboot.
+func (c *DbusClient) SetNextBoot(image string) error {
+	common_utils.IncCounter(common_utils.DBUS_SET_NEXT_BOOT)
+	modName := "image_service"
+	busName := c.busNamePrefix + modName
+	busPath := c.busPathPrefix + modName
+	intName := c.intNamePrefix + modName + ".set_next_boot"
+	err := DbusApi(busName, busPath, intName, 60, image)
+	return err
+}
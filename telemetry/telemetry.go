@@ -44,19 +44,103 @@ type TelemetryConfig struct {
 	ServerCert            *string
 	ServerKey             *string
 	ConfigTableName       *string
+	CertIdentity          *string
 	ZmqAddress            *string
 	ZmqPort               *string
 	Insecure              *bool
 	NoTLS                 *bool
 	AllowNoClientCert     *bool
+	CrlFile               *string
+	Ocsp                  *bool
+	OcspSoftFail          *bool
+	OcspStapleFile        *string
 	JwtRefInt             *uint64
 	JwtValInt             *uint64
+	JwtKeyFile            *string
+	JwtAlg                *string
+	JwtKeyRotateInt       *uint64
 	GnmiTranslibWrite     *bool
 	GnmiNativeWrite       *bool
 	Threshold             *int
 	WithMasterArbitration *bool
 	WithSaveOnSet         *bool
 	IdleConnDuration      *int
+	AuthzPolicy           *string
+	AuthzTable            *string
+	FileDirs              *string
+	RequireImageHash      *bool
+	ImageDir              *string
+	HostImageDir          *string
+	MaxImageSize          *int64
+
+	// Revocation checker of client certificates, nil if disabled
+	revocationChecker *gnmi.CertRevocationChecker
+	// Certificates served to new TLS handshakes
+	tlsCerts *tlsCertStore
+}
+
+// tlsCertStore holds the server certificate and client CAs used by new TLS
+// handshakes. Rotated certificates are swapped in place, so that established
+// connections and their streams are not affected.
+type tlsCertStore struct {
+	cert     atomic.Value // *tls.Certificate
+	clientCA atomic.Value // *x509.CertPool
+}
+
+// loaded reports whether the certificates have been loaded once.
+func (cs *tlsCertStore) loaded() bool {
+	return cs.cert.Load() != nil
+}
+
+func (cs *tlsCertStore) set(cert *tls.Certificate, clientCA *x509.CertPool) {
+	cs.cert.Store(cert)
+	if clientCA != nil {
+		cs.clientCA.Store(clientCA)
+	}
+}
+
+func (cs *tlsCertStore) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cs.cert.Load().(*tls.Certificate), nil
+}
+
+// getConfigForClient returns the handshake config, which is base with the
+// current client CAs.
+func (cs *tlsCertStore) getConfigForClient(base *tls.Config) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	return func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cfg := base.Clone()
+		if pool, ok := cs.clientCA.Load().(*x509.CertPool); ok {
+			cfg.ClientCAs = pool
+		}
+		return cfg, nil
+	}
+}
+
+// reload loads the certificate files again. The current certificates are
+// kept if any file cannot be loaded, e.g. while a rotation is in progress.
+func (cs *tlsCertStore) reload(telemetryCfg *TelemetryConfig) error {
+	certificate, err := tls.LoadX509KeyPair(*telemetryCfg.ServerCert, *telemetryCfg.ServerKey)
+	if err != nil {
+		return fmt.Errorf("could not load server key pair: %v", err)
+	}
+	if *telemetryCfg.OcspStapleFile != "" {
+		if certificate.OCSPStaple, err = ioutil.ReadFile(*telemetryCfg.OcspStapleFile); err != nil {
+			log.Errorf("could not read OCSP staple: %s", err)
+		}
+	}
+	var certPool *x509.CertPool
+	if *telemetryCfg.CaCert != "" {
+		ca, err := ioutil.ReadFile(*telemetryCfg.CaCert)
+		if err != nil {
+			return fmt.Errorf("could not read CA certificate: %v", err)
+		}
+		certPool = x509.NewCertPool()
+		if ok := certPool.AppendCertsFromPEM(ca); !ok {
+			return fmt.Errorf("failed to append CA certificate")
+		}
+	}
+	cs.set(&certificate, certPool)
+	log.V(1).Infof("Reloaded TLS certificates")
+	return nil
 }
 
 func main() {
@@ -100,6 +184,10 @@ func runTelemetry(args []string) error {
 
 	go startGNMIServer(telemetryCfg, cfg, serverControlSignal, stopSignalHandler, &wg)
 
+	if *telemetryCfg.JwtKeyRotateInt > 0 {
+		go gnmi.RotateJwtKeys(time.Duration(*telemetryCfg.JwtKeyRotateInt) * time.Second)
+	}
+
 	wg.Wait()
 	return nil
 }
@@ -152,19 +240,34 @@ func setupFlags(fs *flag.FlagSet) (*TelemetryConfig, *gnmi.Config, error) {
 		ServerCert:            fs.String("server_crt", "", "TLS server certificate"),
 		ServerKey:             fs.String("server_key", "", "TLS server private key"),
 		ConfigTableName:       fs.String("config_table_name", "", "Config table name"),
+		CertIdentity:          fs.String("cert_identity", gnmi.CertIdentityCN, "Client certificate identities mapped to roles, in order of preference - comma separated list of cn, san_dns, san_uri, ou"),
 		ZmqAddress:            fs.String("zmq_address", "", "Orchagent ZMQ address, deprecated, please use zmq_port."),
 		ZmqPort:               fs.String("zmq_port", "", "Orchagent ZMQ port, when not set or empty string telemetry server will switch to Redis based communication channel."),
 		Insecure:              fs.Bool("insecure", false, "Skip providing TLS cert and key, for testing only!"),
 		NoTLS:                 fs.Bool("noTLS", false, "disable TLS, for testing only!"),
 		AllowNoClientCert:     fs.Bool("allow_no_client_auth", false, "When set, telemetry server will request but not require a client certificate."),
+		CrlFile:               fs.String("crl_file", "", "CRL file of revoked client certificates, reloaded on change. Optional."),
+		Ocsp:                  fs.Bool("ocsp", false, "Check client certificates against the OCSP responders they list."),
+		OcspSoftFail:          fs.Bool("ocsp_soft_fail", false, "Accept client certificates whose OCSP status cannot be determined."),
+		OcspStapleFile:        fs.String("ocsp_staple_file", "", "DER encoded OCSP response stapled to the server certificate. Optional."),
 		JwtRefInt:             fs.Uint64("jwt_refresh_int", 900, "Seconds before JWT expiry the token can be refreshed."),
 		JwtValInt:             fs.Uint64("jwt_valid_int", 3600, "Seconds that JWT token is valid for."),
+		JwtKeyFile:            fs.String("jwt_key_file", "", "File persisting the JWT signing keys across restarts. Optional."),
+		JwtAlg:                fs.String("jwt_alg", gnmi.JwtAlgHS256, "JWT signing algorithm - HS256, RS256 or ES256"),
+		JwtKeyRotateInt:       fs.Uint64("jwt_key_rotate_int", 0, "Seconds between JWT signing key rotations, 0 meaning never."),
 		GnmiTranslibWrite:     fs.Bool("gnmi_translib_write", gnmi.ENABLE_TRANSLIB_WRITE, "Enable gNMI translib write for management framework"),
 		GnmiNativeWrite:       fs.Bool("gnmi_native_write", gnmi.ENABLE_NATIVE_WRITE, "Enable gNMI native write"),
 		Threshold:             fs.Int("threshold", 100, "max number of client connections"),
 		WithMasterArbitration: fs.Bool("with-master-arbitration", false, "Enables master arbitration policy."),
 		WithSaveOnSet:         fs.Bool("with-save-on-set", false, "Enables save-on-set."),
 		IdleConnDuration:      fs.Int("idle_conn_duration", 5, "Seconds before server closes idle connections"),
+		AuthzPolicy:           fs.String("authz_policy", "", "Role-based authorization policy file. Optional."),
+		AuthzTable:            fs.String("authz_table", "", "CONFIG_DB table of the role-based authorization policy. Optional."),
+		FileDirs:              fs.String("file_dirs", "", "Comma separated directories accessible through the gNOI File service, which also needs an authorization policy and client authentication. Disabled if empty"),
+		RequireImageHash:      fs.Bool("require_image_hash", false, "Reject gNOI OS Install requests without the image-sha256 metadata."),
+		ImageDir:              fs.String("image_dir", "", "Directory mounted from the host where gNOI OS Install stages images. Install is disabled if empty"),
+		HostImageDir:          fs.String("host_image_dir", "", "Path of image_dir on the host, if it is mounted at another path."),
+		MaxImageSize:          fs.Int64("max_image_size", gnmi.DefaultMaxImageSize, "Maximum size in bytes of the images received by gNOI OS Install."),
 	}
 
 	fs.Var(&telemetryCfg.UserAuth, "client_auth", "Client auth mode(s) - none,cert,password")
@@ -200,6 +303,11 @@ func setupFlags(fs *flag.FlagSet) (*TelemetryConfig, *gnmi.Config, error) {
 		return nil, nil, fmt.Errorf("idle_conn_duration must be >= 0, 0 meaning inf")
 	}
 
+	switch {
+	case *telemetryCfg.AuthzPolicy != "" && *telemetryCfg.AuthzTable != "":
+		return nil, nil, fmt.Errorf("authz_policy and authz_table are mutually exclusive.")
+	}
+
 	switch {
 	case *telemetryCfg.LogLevel < 0:
 		*telemetryCfg.LogLevel = 2
@@ -215,10 +323,25 @@ func setupFlags(fs *flag.FlagSet) (*TelemetryConfig, *gnmi.Config, error) {
 		}
 	}
 
+	if *telemetryCfg.CrlFile != "" || *telemetryCfg.Ocsp {
+		checker, err := gnmi.NewCertRevocationChecker(*telemetryCfg.CrlFile, *telemetryCfg.Ocsp)
+		if err != nil {
+			return nil, nil, err
+		}
+		checker.OcspSoftFail = *telemetryCfg.OcspSoftFail
+		telemetryCfg.revocationChecker = checker
+	}
+
 	// Move to new function
 	gnmi.JwtRefreshInt = time.Duration(*telemetryCfg.JwtRefInt * uint64(time.Second))
 	gnmi.JwtValidInt = time.Duration(*telemetryCfg.JwtValInt * uint64(time.Second))
 
+	jwtKeys, err := gnmi.NewJwtKeyStore(*telemetryCfg.JwtAlg, *telemetryCfg.JwtKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	gnmi.SetJwtKeyStore(jwtKeys)
+
 	cfg := &gnmi.Config{}
 	cfg.Port = int64(*telemetryCfg.Port)
 	cfg.EnableTranslibWrite = bool(*telemetryCfg.GnmiTranslibWrite)
@@ -227,6 +350,23 @@ func setupFlags(fs *flag.FlagSet) (*TelemetryConfig, *gnmi.Config, error) {
 	cfg.Threshold = int(*telemetryCfg.Threshold)
 	cfg.IdleConnDuration = int(*telemetryCfg.IdleConnDuration)
 	cfg.ConfigTableName = *telemetryCfg.ConfigTableName
+	cfg.RequireImageHash = *telemetryCfg.RequireImageHash
+	cfg.ImageDir = *telemetryCfg.ImageDir
+	cfg.HostImageDir = *telemetryCfg.HostImageDir
+	cfg.MaxImageSize = *telemetryCfg.MaxImageSize
+	cfg.CertIdentitySources, err = gnmi.ParseCertIdentitySources(*telemetryCfg.CertIdentity)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, dir := range strings.Split(*telemetryCfg.FileDirs, ",") {
+		if dir = strings.TrimSpace(dir); dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			return nil, nil, fmt.Errorf("file_dirs must be absolute paths: %v", dir)
+		}
+		cfg.FileDirs = append(cfg.FileDirs, filepath.Clean(dir))
+	}
 
 	// TODO: After other dependent projects are migrated to ZmqPort, remove ZmqAddress
 	zmqAddress := *telemetryCfg.ZmqAddress
@@ -265,10 +405,29 @@ func iNotifyCertMonitoring(watcher *fsnotify.Watcher, telemetryCfg *TelemetryCon
 		for {
 			select {
 			case event := <-watcher.Events:
+				if isCrlFile(telemetryCfg, event.Name) {
+					if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
+						// Revocations apply to new connections without server restart
+						if err := telemetryCfg.revocationChecker.ReloadCRL(); err != nil {
+							log.Errorf("Failed to reload CRL: %v", err)
+						}
+					}
+					continue
+				}
 				if event.Name != "" && (filepath.Ext(event.Name) == ".cert" || filepath.Ext(event.Name) == ".crt" ||
 					filepath.Ext(event.Name) == ".cer" || filepath.Ext(event.Name) == ".pem" ||
 					filepath.Ext(event.Name) == ".key") {
 					log.V(1).Infof("Inotify watcher has received event: %v", event)
+					if telemetryCfg.tlsCerts != nil && telemetryCfg.tlsCerts.loaded() {
+						// Swap the certificates of new handshakes, the server keeps running
+						if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
+							if err := telemetryCfg.tlsCerts.reload(telemetryCfg); err != nil {
+								log.V(1).Infof("Keeping current certificates: %v", err)
+							}
+						}
+						// Removed files are expected to be replaced, the loaded ones stay in use
+						continue
+					}
 					if event.Op&fsnotify.Write == fsnotify.Write || event.Op&fsnotify.Create == fsnotify.Create {
 						log.V(1).Infof("Cert File has been modified: %s", event.Name)
 						serverControlSignal <- ServerStart // let server know that a write/create event occurred
@@ -306,10 +465,26 @@ func iNotifyCertMonitoring(watcher *fsnotify.Watcher, telemetryCfg *TelemetryCon
 		done <- true
 	}
 
+	if crlDirectory := filepath.Dir(*telemetryCfg.CrlFile); err == nil && telemetryCfg.revocationChecker != nil &&
+		*telemetryCfg.CrlFile != "" && crlDirectory != telemetryCertDirectory {
+		log.V(1).Infof("Begin CRL monitoring on %s", crlDirectory)
+		if err := watcher.Add(crlDirectory); err != nil {
+			log.Errorf("Received error when adding watcher to CRL directory: %v", err)
+		}
+	}
+
 	<-done
 	log.V(6).Infof("Closing cert rotation monitoring")
 }
 
+// isCrlFile reports whether fileName is the CRL file being checked.
+func isCrlFile(telemetryCfg *TelemetryConfig, fileName string) bool {
+	if telemetryCfg.revocationChecker == nil || *telemetryCfg.CrlFile == "" || fileName == "" {
+		return false
+	}
+	return filepath.Clean(fileName) == filepath.Clean(*telemetryCfg.CrlFile)
+}
+
 func signalHandler(serverControlSignal chan<- ServerControlValue, sigchannel <-chan os.Signal, stopSignalHandler <-chan bool, wg *sync.WaitGroup) {
 	defer wg.Done()
 	select {
@@ -325,6 +500,18 @@ func signalHandler(serverControlSignal chan<- ServerControlValue, sigchannel <-c
 func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverControlSignal chan ServerControlValue, stopSignalHandler chan<- bool, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	if telemetryCfg.tlsCerts == nil {
+		telemetryCfg.tlsCerts = &tlsCertStore{}
+	}
+
+	stopAuthz := make(chan struct{})
+	defer close(stopAuthz)
+	authorizer, err := loadAuthorizer(telemetryCfg, stopAuthz)
+	if err != nil {
+		log.Errorf("Failed to load authorization policy: %v", err)
+		return
+	}
+
 	for {
 		var opts []grpc.ServerOption
 		var certLoaded int32
@@ -369,7 +556,7 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 
 			tlsCfg := &tls.Config{
 				ClientAuth:               tls.RequireAndVerifyClientCert,
-				Certificates:             []tls.Certificate{certificate},
+				GetCertificate:           telemetryCfg.tlsCerts.getCertificate,
 				MinVersion:               tls.VersionTLS12,
 				CurvePreferences:         []tls.CurveID{tls.CurveP521, tls.CurveP384, tls.CurveP256},
 				PreferServerCipherSuites: true,
@@ -383,6 +570,19 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 				},
 			}
 
+			if *telemetryCfg.OcspStapleFile != "" {
+				staple, err := ioutil.ReadFile(*telemetryCfg.OcspStapleFile)
+				if err != nil {
+					log.Errorf("could not read OCSP staple: %s", err)
+				} else {
+					certificate.OCSPStaple = staple
+				}
+			}
+
+			if telemetryCfg.revocationChecker != nil {
+				tlsCfg.VerifyPeerCertificate = telemetryCfg.revocationChecker.VerifyPeerCertificate
+			}
+
 			if *telemetryCfg.AllowNoClientCert {
 				// RequestClientCert will ask client for a certificate but won't
 				// require it to proceed. If certificate is provided, it will be
@@ -415,6 +615,7 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 					continue
 				}
 				tlsCfg.ClientCAs = certPool
+				tlsCfg.GetConfigForClient = telemetryCfg.tlsCerts.getConfigForClient(tlsCfg)
 			} else {
 				if telemetryCfg.UserAuth.Enabled("cert") {
 					telemetryCfg.UserAuth.Unset("cert")
@@ -422,6 +623,7 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 				}
 			}
 
+			telemetryCfg.tlsCerts.set(&certificate, tlsCfg.ClientCAs)
 			atomic.StoreInt32(&certLoaded, 1) // Certs have loaded
 
 			keep_alive_params := keepalive.ServerParameters{
@@ -435,10 +637,10 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 			}
 
 			cfg.UserAuth = telemetryCfg.UserAuth
-
-			gnmi.GenerateJwtSecretKey()
 		}
 
+		cfg.Authorizer = authorizer
+
 		s, err := gnmi.NewServer(cfg, opts)
 		if err != nil {
 			log.Errorf("Failed to create gNMI server: %v", err)
@@ -476,6 +678,28 @@ func startGNMIServer(telemetryCfg *TelemetryConfig, cfg *gnmi.Config, serverCont
 	}
 }
 
+// loadAuthorizer returns the authorizer of the configured policy source. The
+// policy is reloaded on changes of the source until stop is closed.
+func loadAuthorizer(telemetryCfg *TelemetryConfig, stop <-chan struct{}) (gnmi.Authorizer, error) {
+	var authorizer *gnmi.PolicyAuthorizer
+	var err error
+	switch {
+	case *telemetryCfg.AuthzPolicy != "":
+		authorizer, err = gnmi.NewFileAuthorizer(*telemetryCfg.AuthzPolicy)
+	case *telemetryCfg.AuthzTable != "":
+		authorizer, err = gnmi.NewConfigDbAuthorizer(*telemetryCfg.AuthzTable)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = authorizer.Watch(stop); err != nil {
+		log.Errorf("Authorization policy changes are not watched: %v", err)
+	}
+	return authorizer, nil
+}
+
 func computeSHA512Checksum(file string) {
 	currentTime := time.Now().UTC()
 	f, err := os.Open(file)
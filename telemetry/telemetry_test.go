@@ -904,6 +904,186 @@ func TestINotifyCertMonitoringAddWatcherError(t *testing.T) {
 	}
 }
 
+func TestINotifyCertMonitoringCrlReload(t *testing.T) {
+	testServerCert := "../testdata/certs/testserver.cert"
+	testServerKey := "../testdata/certs/testserver.key"
+
+	crlDir, err := ioutil.TempDir("", "crl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(crlDir)
+	crlFile := crlDir + "/ca.crl"
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate {
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name {
+			Organization: []string{"Mock CA"},
+		},
+		NotBefore: time.Now(),
+		NotAfter: time.Now().Add(time.Hour),
+		KeyUsage: x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA: true,
+	}
+	caBytes, _ := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	ca, _ := x509.ParseCertificate(caBytes)
+	leafTmpl := &x509.Certificate {
+		SerialNumber: big.NewInt(2),
+		Subject: pkix.Name {
+			CommonName: "client",
+		},
+		NotBefore: time.Now(),
+		NotAfter: time.Now().Add(time.Hour),
+	}
+	leafBytes, _ := x509.CreateCertificate(rand.Reader, leafTmpl, ca, &caKey.PublicKey, caKey)
+	leaf, _ := x509.ParseCertificate(leafBytes)
+
+	writeCRL := func(revoked []pkix.RevokedCertificate) {
+		crlBytes, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList {
+			Number: big.NewInt(int64(len(revoked))),
+			ThisUpdate: time.Now(),
+			NextUpdate: time.Now().Add(time.Hour),
+			RevokedCertificates: revoked,
+		}, ca, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(crlFile, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlBytes}), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeCRL(nil)
+
+	originalArgs := os.Args
+	defer func() {
+		os.Args = originalArgs
+	}()
+
+	fs := flag.NewFlagSet("testiNotifyCertMonitoring", flag.ContinueOnError)
+	os.Args = []string{"cmd", "-v=2", "-port", "8080", "-server_crt", testServerCert, "-server_key", testServerKey, "-crl_file", crlFile}
+	telemetryCfg, _, err := setupFlags(fs)
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+	checker := telemetryCfg.revocationChecker
+	chains := [][]*x509.Certificate{{leaf, ca}}
+	if err = checker.VerifyPeerCertificate(nil, chains); err != nil {
+		t.Fatalf("Expected certificate to be accepted, got err %v", err)
+	}
+
+	serverControlSignal := make(chan ServerControlValue, 1)
+	testReadySignal := make(chan int, 1)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+
+	go iNotifyCertMonitoring(watcher, telemetryCfg, serverControlSignal, testReadySignal, nil)
+
+	<-testReadySignal
+	// Let the watcher be added to the CRL directory
+	time.Sleep(100 * time.Millisecond)
+
+	writeCRL([]pkix.RevokedCertificate{{SerialNumber: leaf.SerialNumber, RevocationTime: time.Now()}})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for checker.VerifyPeerCertificate(nil, chains) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected certificate to be rejected after CRL update")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	select {
+	case val := <-serverControlSignal:
+		t.Errorf("Expected no server restart on CRL update, got %d", val)
+	default:
+	}
+}
+
+func TestINotifyCertMonitoringHotReload(t *testing.T) {
+	certDir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(certDir)
+	testServerCert := certDir + "/testserver.cert"
+	testServerKey := certDir + "/testserver.key"
+	if err = saveCertKeyPair(testServerCert, testServerKey); err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+
+	originalArgs := os.Args
+	defer func() {
+		os.Args = originalArgs
+	}()
+
+	fs := flag.NewFlagSet("testiNotifyCertMonitoring", flag.ContinueOnError)
+	os.Args = []string{"cmd", "-v=2", "-port", "8080", "-server_crt", testServerCert, "-server_key", testServerKey}
+	telemetryCfg, _, err := setupFlags(fs)
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+	telemetryCfg.tlsCerts = &tlsCertStore{}
+	if err = telemetryCfg.tlsCerts.reload(telemetryCfg); err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+	current := func() []byte {
+		cert, _ := telemetryCfg.tlsCerts.getCertificate(nil)
+		return cert.Certificate[0]
+	}
+	initial := current()
+
+	serverControlSignal := make(chan ServerControlValue, 1)
+	testReadySignal := make(chan int, 1)
+	var certLoaded int32
+	atomic.StoreInt32(&certLoaded, 1)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+
+	go iNotifyCertMonitoring(watcher, telemetryCfg, serverControlSignal, testReadySignal, &certLoaded)
+
+	<-testReadySignal
+
+	// Rotate certs
+	if err = saveCertKeyPair(testServerCert, testServerKey); err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for reflect.DeepEqual(current(), initial) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected certificate to be swapped after rotation")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	// Deleted certs keep the loaded ones in use
+	rotated := current()
+	if err = os.Remove(testServerCert); err != nil {
+		t.Fatalf("Expected err to be nil, got err %v", err)
+	}
+	time.Sleep(500 * time.Millisecond)
+	if !reflect.DeepEqual(current(), rotated) {
+		t.Errorf("Expected certificate to be kept after deletion")
+	}
+
+	select {
+	case val := <-serverControlSignal:
+		t.Errorf("Expected no server restart on cert rotation, got %d", val)
+	default:
+	}
+}
+
 func TestSignalHandler(t *testing.T) {
 	testHandlerSyscall(t, syscall.SIGTERM)
 	testHandlerSyscall(t, syscall.SIGQUIT)
@@ -1,5 +1,5 @@
 import json
-from utils import gnmi_set, gnmi_get, gnmi_get_with_encoding, gnmi_get_proto
+from utils import gnmi_set, gnmi_get, gnmi_get_with_encoding, gnmi_get_proto, gnmi_subscribe_poll
 
 import pytest
 
@@ -485,3 +485,26 @@ class TestGNMIApplDb:
 
         ret, msg = gnmi_set(delete_list, [], [])
         assert ret == 0, msg
+
+    @pytest.mark.multidb
+    def test_gnmi_poll_01(self):
+        clear_appl_db('DASH_QOS')
+        path = '/sonic-db:APPL_DB/dpu0/DASH_QOS'
+        value = {
+            'qos_01': {'bw': '54321', 'cps': '1000', 'flows': '300'}
+        }
+        text = json.dumps(value)
+        file_name = 'update.txt'
+        file_object = open(file_name, 'w')
+        file_object.write(text)
+        file_object.close()
+        update_list = [path + ':@./' + file_name]
+
+        ret, msg = gnmi_set([], update_list, [])
+        assert ret == 0, msg
+
+        cnt = 3
+        interval = 1
+        ret, msg = gnmi_subscribe_poll('/APPL_DB/dpu0/_DASH_QOS', interval, cnt, timeout=0)
+        assert ret == 0, 'Fail to subscribe: ' + msg
+        assert msg.count('54321') == cnt, 'Invalid result: ' + msg
@@ -11,25 +11,63 @@ class TestGNOI:
         assert 'time' in msg, 'Invalid response: %s'%msg
 
     def test_gnoi_reboot(self):
-        ret, old_cnt = gnmi_dump('DBUS config reload')
+        ret, old_cnt = gnmi_dump('DBUS reboot')
         assert ret == 0, 'Fail to read counter'
 
         ret, msg = gnoi_reboot(1, 0, 'Test reboot')
         assert ret == 0, msg
 
+        ret, new_cnt = gnmi_dump('DBUS reboot')
+        assert ret == 0, 'Fail to read counter'
+        assert new_cnt == old_cnt+1, 'DBUS API is not invoked'
+
+    def test_gnoi_reboot_config_reload(self):
+        ret, old_cnt = gnmi_dump('DBUS config reload')
+        assert ret == 0, 'Fail to read counter'
+
+        ret, msg = gnoi_reboot(0, 0, 'Test reboot')
+        assert ret == 0, msg
+
         ret, new_cnt = gnmi_dump('DBUS config reload')
         assert ret == 0, 'Fail to read counter'
         assert new_cnt == old_cnt+1, 'DBUS API is not invoked'
 
+    def test_gnoi_reboot_unsupported_method(self):
+        ret, msg = gnoi_reboot(3, 0, 'Test reboot')
+        assert ret != 0, 'Reboot with HALT method should fail' + msg
+        assert 'Unimplemented' in msg
+
     def test_gnoi_rebootstatus(self):
         ret, msg = gnoi_rebootstatus()
-        assert ret != 0, 'RebootStatus should fail' + msg
-        assert 'Unimplemented' in msg
+        assert ret == 0, msg
+        assert '"active"' not in msg, 'No reboot should be pending: ' + msg
 
     def test_gnoi_cancelreboot(self):
         ret, msg = gnoi_cancelreboot('Test reboot')
-        assert ret != 0, 'CancelReboot should fail' + msg
-        assert 'Unimplemented' in msg
+        assert ret == 0, msg
+
+    def test_gnoi_delayed_reboot(self):
+        ret, old_cnt = gnmi_dump('DBUS reboot')
+        assert ret == 0, 'Fail to read counter'
+
+        ret, msg = gnoi_reboot(1, 3600 * 1000000000, 'Test delayed reboot')
+        assert ret == 0, msg
+
+        ret, msg = gnoi_rebootstatus()
+        assert ret == 0, msg
+        assert '"active":true' in msg, 'Reboot should be pending: ' + msg
+        assert 'Test delayed reboot' in msg, 'Invalid reason: ' + msg
+
+        ret, msg = gnoi_cancelreboot('Test reboot')
+        assert ret == 0, msg
+
+        ret, msg = gnoi_rebootstatus()
+        assert ret == 0, msg
+        assert '"active"' not in msg, 'Reboot should be cancelled: ' + msg
+
+        ret, new_cnt = gnmi_dump('DBUS reboot')
+        assert ret == 0, 'Fail to read counter'
+        assert new_cnt == old_cnt, 'DBUS API invoked unexpectedly'
 
     def test_gnoi_killprocess(self):
         ret, old_cnt = gnmi_dump('DBUS stop service')
@@ -43,6 +81,18 @@ class TestGNOI:
         assert ret == 0, 'Fail to read counter'
         assert new_cnt == old_cnt+1, 'DBUS API is not invoked'
 
+    def test_gnoi_killprocess_signal(self):
+        ret, old_cnt = gnmi_dump('DBUS kill process')
+        assert ret == 0, 'Fail to read counter'
+
+        json_data = '{"name": "snmp", "pid": 3, "signal": 3}'
+        ret, msg = gnoi_kill_process(json_data)
+        assert ret == 0, msg
+
+        ret, new_cnt = gnmi_dump('DBUS kill process')
+        assert ret == 0, 'Fail to read counter'
+        assert new_cnt == old_cnt+1, 'DBUS API is not invoked'
+
     def test_gnoi_restartprocess_unimplemented(self):
         ret, old_cnt = gnmi_dump('DBUS restart service')
         assert ret == 0, 'Fail to read counter'
@@ -96,13 +96,28 @@ func ConvertToURI(prefix, path *gnmipb.Path, opts ...pathutil.PathValidatorOpt)
 	return ygot.PathToString(fullPath)
 }
 
+/* Map gNMI GetRequest data type to translib content query parameter. */
+func getContentQueryParam(dataType gnmipb.GetRequest_DataType) string {
+	switch dataType {
+	case gnmipb.GetRequest_CONFIG:
+		return "config"
+	case gnmipb.GetRequest_STATE:
+		return "state"
+	case gnmipb.GetRequest_OPERATIONAL:
+		return "operational"
+	}
+	/* Empty content retrieves all data. */
+	return ""
+}
+
 /* Fill the values from TransLib. */
-func TranslProcessGet(uriPath string, op *string, ctx context.Context) (*gnmipb.TypedValue, error) {
+func TranslProcessGet(uriPath string, op *string, ctx context.Context, dataType gnmipb.GetRequest_DataType) (*gnmipb.TypedValue, error) {
 	var jv []byte
 	var data []byte
 	rc, _ := common_utils.GetContext(ctx)
 
 	req := translib.GetRequest{Path:uriPath, User: translib.UserRoles{Name: rc.Auth.User, Roles: rc.Auth.Roles}}
+	req.QueryParams.Content = getContentQueryParam(dataType)
 	if rc.BundleVersion != nil {
 		nver, err := translib.NewVersion(*rc.BundleVersion)
 		if err != nil {